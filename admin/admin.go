@@ -0,0 +1,160 @@
+// Package admin provides the HTTP server of the administrative endpoints of the application,
+// e.g. health checks, metrics, profiling, version, config and log level control.
+// The routes are pluggable, so the same server can host all of them on one port,
+// or several servers can be started on separate ports.
+package admin
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/tombenke/go-12f-common/v2/log"
+	"github.com/tombenke/go-12f-common/v2/oti"
+)
+
+// ErrTLSConfig is returned by Startup if only one of the TLS certificate and key files is set
+var ErrTLSConfig = errors.New("both the TLS certificate and key files must be set")
+
+// Config holds the configuration parameters of an admin server
+type Config struct {
+	// Address is the bind address. Binds to all interfaces if empty.
+	Address string
+	// Port to listen on. A random free port is used if zero, see Addr().
+	Port uint
+	// Username and Password enable basic authentication of the non-public routes if both are set
+	Username string
+	Password string
+	// TLSCertFile and TLSKeyFile enable TLS if both are set. Setting only one of them is an error.
+	TLSCertFile string
+	TLSKeyFile  string
+}
+
+// Server is the HTTP server of the admin routes
+type Server struct {
+	config   Config
+	mux      *http.ServeMux
+	server   *http.Server
+	listener net.Listener
+	wg       *sync.WaitGroup
+}
+
+// RouteOption configures a route of the admin server
+type RouteOption func(*route)
+
+type route struct {
+	public bool
+}
+
+// Public makes the route accessible without basic authentication, e.g. for the health check probes
+func Public() RouteOption {
+	return func(r *route) {
+		r.public = true
+	}
+}
+
+// NewServer creates an admin server instance
+func NewServer(wg *sync.WaitGroup, config Config) *Server {
+	return &Server{wg: wg, config: config, mux: http.NewServeMux()}
+}
+
+// Handle registers the handler for the given pattern
+func (s *Server) Handle(pattern string, handler http.Handler, options ...RouteOption) {
+	r := route{}
+	for _, option := range options {
+		option(&r)
+	}
+	if !r.public && s.config.Username != "" && s.config.Password != "" {
+		handler = basicAuth(s.config.Username, s.config.Password, handler)
+	}
+	s.mux.Handle(pattern, handler)
+}
+
+// HandleFunc registers the handler function for the given pattern
+func (s *Server) HandleFunc(pattern string, handler http.HandlerFunc, options ...RouteOption) {
+	s.Handle(pattern, handler, options...)
+}
+
+// Startup opens the listener of the server, then starts serving the routes in a separate goroutine.
+// It returns with error if the server can not listen on the address, e.g. the port is already taken,
+// or only one of the TLS certificate and key files is set.
+func (s *Server) Startup(ctx context.Context) error {
+	_, logger := s.getLogger(ctx)
+	if (s.config.TLSCertFile == "") != (s.config.TLSKeyFile == "") {
+		return fmt.Errorf("%w: cert file: %q, key file: %q", ErrTLSConfig, s.config.TLSCertFile, s.config.TLSKeyFile)
+	}
+	listener, err := net.Listen("tcp", net.JoinHostPort(s.config.Address, fmt.Sprintf("%d", s.config.Port)))
+	if err != nil {
+		return fmt.Errorf("failed to listen for admin server. %w", err)
+	}
+	s.listener = listener
+	s.server = &http.Server{Handler: s.mux}
+	logger.Info("Starting up", "address", s.Addr(), "tls", s.tlsEnabled())
+
+	// Start the blocking server call in a separate thread
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		var err error
+		if s.tlsEnabled() {
+			err = s.server.ServeTLS(listener, s.config.TLSCertFile, s.config.TLSKeyFile)
+		} else {
+			err = s.server.Serve(listener)
+		}
+		if errors.Is(err, http.ErrServerClosed) {
+			logger.Info("Server closed")
+		} else if err != nil {
+			logger.Error("Error serving admin server", log.ErrorAttr(err))
+		}
+	}()
+	return nil
+}
+
+// Shutdown gracefully shuts down the server within the deadline of ctx
+func (s *Server) Shutdown(ctx context.Context) error {
+	_, logger := s.getLogger(ctx)
+	logger.Info("Shutdown")
+	if s.server == nil {
+		return nil
+	}
+	if err := s.server.Shutdown(ctx); err != nil {
+		return fmt.Errorf("failed to shut down admin server. %w", err)
+	}
+	return nil
+}
+
+// Addr returns with the address the server listens on, or nil if it has not been started
+func (s *Server) Addr() net.Addr {
+	if s.listener == nil {
+		return nil
+	}
+	return s.listener.Addr()
+}
+
+func (s *Server) tlsEnabled() bool {
+	return s.config.TLSCertFile != "" && s.config.TLSKeyFile != ""
+}
+
+func (s *Server) getLogger(ctx context.Context) (context.Context, *slog.Logger) {
+	return log.With(ctx, string(oti.FieldComponent), "AdminServer")
+}
+
+// basicAuth wraps the handler with basic authentication
+func basicAuth(username string, password string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		u, p, ok := r.BasicAuth()
+		if !ok ||
+			subtle.ConstantTimeCompare([]byte(u), []byte(username)) != 1 ||
+			subtle.ConstantTimeCompare([]byte(p), []byte(password)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Basic realm="admin"`)
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,112 @@
+package admin_test
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tombenke/go-12f-common/v2/admin"
+	"github.com/tombenke/go-12f-common/v2/log"
+)
+
+func TestAdminServer(t *testing.T) {
+	require.NoError(t, log.SetupDefault("info", "text"))
+	wg := sync.WaitGroup{}
+	server := admin.NewServer(&wg, admin.Config{Address: "127.0.0.1", Username: "admin", Password: "secret"})
+	server.HandleFunc("/public", func(w http.ResponseWriter, r *http.Request) {}, admin.Public())
+	server.HandleFunc(admin.VersionPath, admin.VersionHandler())
+	server.HandleFunc(admin.LogLevelPath, admin.LogLevelHandler())
+	server.RegisterPprof()
+	require.NoError(t, server.Startup(context.Background()))
+	baseURL := "http://" + server.Addr().String()
+
+	assert.Equal(t, http.StatusOK, doRequest(t, http.MethodGet, baseURL+"/public", "", false))
+	assert.Equal(t, http.StatusUnauthorized, doRequest(t, http.MethodGet, baseURL+admin.VersionPath, "", false))
+	assert.Equal(t, http.StatusOK, doRequest(t, http.MethodGet, baseURL+admin.VersionPath, "", true))
+	assert.Equal(t, http.StatusOK, doRequest(t, http.MethodGet, baseURL+admin.PprofPath, "", true))
+
+	assert.Equal(t, http.StatusOK, doRequest(t, http.MethodPut, baseURL+admin.LogLevelPath, `{"level": "debug"}`, true))
+	assert.Equal(t, slog.LevelDebug, log.GetLevel())
+	assert.Equal(t, http.StatusBadRequest, doRequest(t, http.MethodPut, baseURL+admin.LogLevelPath, `{"level": "verbose"}`, true))
+	assert.Equal(t, slog.LevelDebug, log.GetLevel())
+	assert.Equal(t, http.StatusBadRequest, doRequest(t, http.MethodPut, baseURL+admin.LogLevelPath, `{"level": "warn", "revertAfter": "soon"}`, true))
+	assert.Equal(t, http.StatusOK, doRequest(t, http.MethodPut, baseURL+admin.LogLevelPath, `{"level": "warn", "revertAfter": "1h"}`, true))
+	assert.Equal(t, slog.LevelWarn, log.GetLevel())
+	_, scheduled := log.LevelRevertAt()
+	assert.True(t, scheduled)
+
+	assert.Equal(t, http.StatusBadRequest, doRequest(t, http.MethodPut, baseURL+admin.LogLevelPath, `{"components": {"Worker": "verbose"}}`, true))
+	assert.Equal(t, http.StatusOK, doRequest(t, http.MethodPut, baseURL+admin.LogLevelPath, `{"components": {"Worker": "debug"}}`, true))
+	assert.Equal(t, map[string]slog.Level{"Worker": slog.LevelDebug}, log.ComponentLevels())
+	assert.Equal(t, slog.LevelWarn, log.GetLevel())
+
+	require.NoError(t, server.Shutdown(context.Background()))
+	wg.Wait()
+}
+
+func TestAdminServerPortTaken(t *testing.T) {
+	wg := sync.WaitGroup{}
+	server := admin.NewServer(&wg, admin.Config{Address: "127.0.0.1"})
+	require.NoError(t, server.Startup(context.Background()))
+
+	port := server.Addr().(*net.TCPAddr).Port
+	other := admin.NewServer(&wg, admin.Config{Address: "127.0.0.1", Port: uint(port)})
+	assert.Error(t, other.Startup(context.Background()))
+
+	require.NoError(t, server.Shutdown(context.Background()))
+	wg.Wait()
+}
+
+func TestAdminServerHalfConfiguredTLS(t *testing.T) {
+	wg := sync.WaitGroup{}
+	for _, config := range []admin.Config{
+		{Address: "127.0.0.1", TLSCertFile: "cert.pem"},
+		{Address: "127.0.0.1", TLSKeyFile: "key.pem"},
+	} {
+		server := admin.NewServer(&wg, config)
+		assert.ErrorIs(t, server.Startup(context.Background()), admin.ErrTLSConfig)
+		assert.Nil(t, server.Addr())
+	}
+	wg.Wait()
+}
+
+func doRequest(t *testing.T, method string, url string, body string, withAuth bool) int {
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	require.NoError(t, err)
+	if withAuth {
+		req.SetBasicAuth("admin", "secret")
+	}
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, res.Body.Close())
+	return res.StatusCode
+}
+
+func TestLogsHandler(t *testing.T) {
+	require.NoError(t, log.SetupDefault("info", "text"))
+	defer log.SetupRecentLogs(0, 0, slog.LevelDebug)
+
+	res := httptest.NewRecorder()
+	admin.LogsHandler()(res, httptest.NewRequest(http.MethodGet, admin.LogsPath, nil))
+	assert.Equal(t, http.StatusNotFound, res.Code)
+
+	log.SetupRecentLogs(10, 0, slog.LevelDebug)
+	log.DebugContext(context.Background(), "recent", "token", "abc")
+	res = httptest.NewRecorder()
+	admin.LogsHandler()(res, httptest.NewRequest(http.MethodGet, admin.LogsPath, nil))
+	assert.Equal(t, http.StatusOK, res.Code)
+	records := []map[string]any{}
+	require.NoError(t, json.Unmarshal(res.Body.Bytes(), &records))
+	require.Len(t, records, 1)
+	assert.Equal(t, "recent", records[0]["msg"])
+	assert.Equal(t, "DEBUG", records[0]["level"])
+	assert.Equal(t, log.RedactedValue, records[0]["token"])
+}
@@ -0,0 +1,207 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"time"
+
+	"github.com/tombenke/go-12f-common/v2/buildinfo"
+	"github.com/tombenke/go-12f-common/v2/log"
+)
+
+const (
+	RouteVersion  = "version"
+	RouteConfig   = "config"
+	RoutePprof    = "pprof"
+	RouteLogLevel = "loglevel"
+	RouteDrain    = "drain"
+	RouteLogs     = "logs"
+
+	VersionPath  = "/version"
+	ConfigPath   = "/config"
+	PprofPath    = "/debug/pprof/"
+	LogLevelPath = "/loglevel"
+	DrainPath    = "/drain"
+	LogsPath     = "/logs"
+	MetricsPath  = "/metrics"
+)
+
+// RegisterPprof registers the handlers of the net/http/pprof package under the /debug/pprof/ path
+func (s *Server) RegisterPprof(options ...RouteOption) {
+	s.HandleFunc(PprofPath, pprof.Index, options...)
+	s.HandleFunc(PprofPath+"cmdline", pprof.Cmdline, options...)
+	s.HandleFunc(PprofPath+"profile", pprof.Profile, options...)
+	s.HandleFunc(PprofPath+"symbol", pprof.Symbol, options...)
+	s.HandleFunc(PprofPath+"trace", pprof.Trace, options...)
+}
+
+// VersionHandler responds with the build information of the application
+func VersionHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{
+			"app":       buildinfo.AppName(),
+			"version":   buildinfo.Version(),
+			"goVersion": runtime.Version(),
+		})
+	}
+}
+
+// ConfigHandler responds with the configuration of the application, with the sensitive values redacted, see log.Redact
+func ConfigHandler(config any) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, log.Redact(config))
+	}
+}
+
+// logLevel is the request and response body of the log level endpoint
+type logLevel struct {
+	Level string `json:"level,omitempty"`
+	// RevertAfter is the optional duration after which the level reverts, e.g. "15m"
+	RevertAfter string     `json:"revertAfter,omitempty"`
+	RevertAt    *time.Time `json:"revertAt,omitempty"`
+	// Components are the levels of the components, that override the level of the default logger
+	Components map[string]string `json:"components,omitempty"`
+}
+
+// LogLevelHandler responds with the current level of the default logger and the levels of the components on GET,
+// and changes them on PUT with a `{"level": "debug", "components": {"Worker": "warn"}}` body.
+// The level reverts after the optional `revertAfter` duration of the body, or the `log-level-revert-after` config parameter.
+func LogLevelHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			body := logLevel{}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+				return
+			}
+			if err := changeLogLevel(r.Context(), body); err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+				return
+			}
+		default:
+			w.Header().Set("Allow", "GET, PUT")
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": http.StatusText(http.StatusMethodNotAllowed)})
+			return
+		}
+		response := logLevel{Level: log.LevelName(log.GetLevel()), Components: map[string]string{}}
+		if revertAt, ok := log.LevelRevertAt(); ok {
+			response.RevertAt = &revertAt
+		}
+		for component, level := range log.ComponentLevels() {
+			response.Components[component] = log.LevelName(level)
+		}
+		writeJSON(w, http.StatusOK, response)
+	}
+}
+
+// changeLogLevel validates the whole request body first, then applies the changes
+func changeLogLevel(ctx context.Context, body logLevel) error {
+	if body.Level == "" && body.Components == nil {
+		return errors.New("neither level nor components are set")
+	}
+
+	var level slog.Level
+	var revertAfter time.Duration
+	var err error
+	if body.Level != "" {
+		if level, err = log.ParseLevel(body.Level); err != nil {
+			return err
+		}
+		if body.RevertAfter != "" {
+			if revertAfter, err = time.ParseDuration(body.RevertAfter); err != nil {
+				return fmt.Errorf("failed to parse revertAfter. %w", err)
+			}
+		}
+	}
+	components := make(map[string]slog.Level, len(body.Components))
+	for component, name := range body.Components {
+		if components[component], err = log.ParseLevel(name); err != nil {
+			return fmt.Errorf("failed to parse the log level of %s. %w", component, err)
+		}
+	}
+
+	if body.Level != "" {
+		log.InfoContext(ctx, "Changing log level", "from", log.LevelName(log.GetLevel()), "to", log.LevelName(level), "revertAfter", body.RevertAfter)
+		if revertAfter > 0 {
+			log.SetLevelFor(level, revertAfter)
+		} else {
+			log.SetLevel(level)
+		}
+	}
+	if body.Components != nil {
+		log.InfoContext(ctx, "Changing component log levels", "to", body.Components)
+		log.SetComponentLevels(components)
+	}
+	return nil
+}
+
+// LogsHandler responds with the recent records of the default logger kept in its ring buffer, the oldest first,
+// see log.SetupRecentLogs
+func LogsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		records, ok := log.RecentLogs()
+		if !ok {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "the recent logs are not kept"})
+			return
+		}
+		writeJSON(w, http.StatusOK, records)
+	}
+}
+
+// Drainer is an application that can be put into drain mode, e.g. for maintenance
+type Drainer interface {
+	Drain(ctx context.Context)
+	Resume(ctx context.Context)
+	Draining() bool
+}
+
+// drainStatus is the request and response body of the drain endpoint
+type drainStatus struct {
+	Draining bool `json:"draining"`
+}
+
+// DrainHandler responds with the drain mode of the application on GET,
+// and enters or leaves the drain mode on PUT with a `{"draining": true}` body.
+func DrainHandler(drainer Drainer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			body := drainStatus{}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+				return
+			}
+			if body.Draining {
+				drainer.Drain(r.Context())
+			} else {
+				drainer.Resume(r.Context())
+			}
+		default:
+			w.Header().Set("Allow", "GET, PUT")
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": http.StatusText(http.StatusMethodNotAllowed)})
+			return
+		}
+		writeJSON(w, http.StatusOK, drainStatus{Draining: drainer.Draining()})
+	}
+}
+
+// writeJSON writes the body as indented JSON with the given status code
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "    ")
+	if err := encoder.Encode(body); err != nil {
+		log.ErrorContext(context.Background(), "Failed to write response", log.ErrorAttr(err))
+	}
+}
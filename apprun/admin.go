@@ -0,0 +1,63 @@
+package apprun
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/tombenke/go-12f-common/v2/admin"
+	"github.com/tombenke/go-12f-common/v2/log"
+	"github.com/tombenke/go-12f-common/v2/oti"
+)
+
+// newAdminServer creates the admin server, and registers the routes enabled by the config
+func (ar *ApplicationRunner) newAdminServer(ctx context.Context) *admin.Server {
+	adminServer := admin.NewServer(ar.wg, admin.Config{
+		Address:     ar.config.AdminAddress,
+		Port:        ar.config.AdminPort,
+		Username:    ar.config.AdminUsername,
+		Password:    ar.config.AdminPassword,
+		TLSCertFile: ar.config.AdminTLSCertFile,
+		TLSKeyFile:  ar.config.AdminTLSKeyFile,
+	})
+
+	for _, route := range ar.config.AdminRoutes {
+		switch strings.ToLower(strings.TrimSpace(route)) {
+		case admin.RouteVersion:
+			adminServer.HandleFunc(admin.VersionPath, admin.VersionHandler())
+		case admin.RouteConfig:
+			adminServer.HandleFunc(admin.ConfigPath, admin.ConfigHandler(map[string]any{
+				"runner": ar.config,
+				"app":    ar.appConfig,
+			}))
+		case admin.RoutePprof:
+			adminServer.RegisterPprof()
+		case admin.RouteLogLevel:
+			adminServer.HandleFunc(admin.LogLevelPath, admin.LogLevelHandler())
+		case admin.RouteLogs:
+			adminServer.HandleFunc(admin.LogsPath, admin.LogsHandler())
+		case admin.RouteDrain:
+			adminServer.HandleFunc(admin.DrainPath, admin.DrainHandler(ar))
+		default:
+			log.WarnContext(ctx, "Unknown admin route", "route", route)
+		}
+	}
+	return adminServer
+}
+
+// sharesAdminServer tells whether the endpoints listening on the port should be hosted by the admin server
+func (ar *ApplicationRunner) sharesAdminServer(port uint) bool {
+	return port == ar.config.AdminPort
+}
+
+// prometheusExporterEnabled tells whether the metrics are exported via the Prometheus exporter
+func (ar *ApplicationRunner) prometheusExporterEnabled() bool {
+	return oti.MetricExporterType(strings.ToLower(ar.config.OtelConfig.OtelMetricsExporter)) == oti.MetricExporterTypePrometheus
+}
+
+// adminServerNeeded tells whether the admin server has anything to serve
+func (ar *ApplicationRunner) adminServerNeeded() bool {
+	return slices.ContainsFunc(ar.config.AdminRoutes, func(route string) bool { return strings.TrimSpace(route) != "" }) ||
+		ar.sharesAdminServer(ar.config.HealthCheckPort) ||
+		(ar.prometheusExporterEnabled() && ar.sharesAdminServer(uint(ar.config.OtelConfig.OtelExporterPrometheusPort))) //nolint:gosec // port is never negative
+}
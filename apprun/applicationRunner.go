@@ -7,12 +7,14 @@ import (
 	"os"
 	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/failsafe-go/failsafe-go"
 	"github.com/failsafe-go/failsafe-go/retrypolicy"
 	"github.com/google/uuid"
 	"github.com/spf13/cobra"
+	"github.com/tombenke/go-12f-common/v2/admin"
 	"github.com/tombenke/go-12f-common/v2/config"
 	"github.com/tombenke/go-12f-common/v2/gsd"
 	"github.com/tombenke/go-12f-common/v2/healthcheck"
@@ -27,6 +29,21 @@ type HealthCheckHook interface {
 	Check(ctx context.Context) error
 }
 
+// ComponentNamer may be implemented by the components to name them in the health reports and metrics.
+// Components that do not implement it are named after their type.
+type ComponentNamer interface {
+	// Returns the name of the component
+	ComponentName() string
+}
+
+// CriticalityHook may be implemented by the components to declare the criticality of their health check.
+// Components that do not implement it are critical.
+type CriticalityHook interface {
+	// If it returns false, a failing health check of the component does not make the application unready,
+	// only degrades the readiness status to warn.
+	Critical() bool
+}
+
 // Interface that defines a component's life-cycle management functions.
 type ComponentLifecycleManager interface {
 	HealthCheckHook
@@ -68,13 +85,38 @@ func MakeAndRun[T config.Configurer](appConfig T, appFactory func(T) (Applicatio
 		if err := appConfig.LoadConfig(cmd.Flags()); err != nil {
 			return err
 		}
-		log.SetupDefault(config.LogLevel, config.LogFormat)
+		logOutputs, err := log.ParseOutputs(config.LogOutputs, config.LogFormat, config.logRotation())
+		if err != nil {
+			return fmt.Errorf("failed to parse log-output. %w", err)
+		}
+		if err := log.SetupDefaultOutputs(config.LogLevel, logOutputs); err != nil {
+			return fmt.Errorf("failed to setup the logger. %w", err)
+		}
+		log.SetLevelRevertAfter(config.LogLevelRevertAfter)
+		log.SetSampling(log.SamplingConfig{
+			Interval:   config.LogSamplingInterval,
+			First:      config.LogSamplingFirst,
+			Thereafter: config.LogSamplingThereafter,
+		})
+		componentLevels, err := log.ParseComponentLevels(config.LogLevels)
+		if err != nil {
+			return fmt.Errorf("failed to parse log-levels. %w", err)
+		}
+		log.SetComponentLevels(componentLevels)
+		if config.LogBufferSize > 0 {
+			bufferLevel, err := log.ParseLevel(config.LogBufferLevel)
+			if err != nil {
+				return fmt.Errorf("failed to parse log-buffer-level. %w", err)
+			}
+			log.SetupRecentLogs(config.LogBufferSize, config.LogBufferWarnSize, bufferLevel)
+		}
 
 		app, err := appFactory(appConfig)
 		if err != nil {
 			return fmt.Errorf("failed to create application. %w", err)
 		}
 		appRunner := NewApplicationRunner(config, app)
+		appRunner.appConfig = appConfig
 		return appRunner.Run()
 	}
 	if err := rootCmd.Execute(); err != nil {
@@ -86,25 +128,38 @@ func MakeAndRun[T config.Configurer](appConfig T, appFactory func(T) (Applicatio
 // ApplicationRunner is the object, that holds the application,
 // and all the supporting components that are needed for a 12-factor application
 type ApplicationRunner struct {
-	config *Config
-	app    Application
-	wg     *sync.WaitGroup
+	config    *Config
+	app       Application
+	wg        *sync.WaitGroup
+	state     atomic.Int32
+	watchdogs *watchdogRegistry
+	inFlight  *inFlightCounter
+	draining  atomic.Bool
+	appConfig any
+	// healthCheck serves the probes, its cache is invalidated when the state or the drain mode changes
+	healthCheck *healthcheck.HealthCheck
 }
 
 // NewApplicationRunner creates a new ApplicationRunner instance
 func NewApplicationRunner(config *Config, app Application) *ApplicationRunner {
 	return &ApplicationRunner{
-		config: config,
-		app:    app,
-		wg:     &sync.WaitGroup{},
+		config:    config,
+		app:       app,
+		wg:        &sync.WaitGroup{},
+		watchdogs: newWatchdogRegistry(config.LivenessWatchdogTimeout),
+		inFlight:  &inFlightCounter{},
 	}
 }
 
 // Run() runs the application, that means it calls the Startup() method of the application instance,
 // and steps into the execution loop, that runs until the application receives signal to shut it down.
 func (ar *ApplicationRunner) Run() error {
+	defer log.DumpRecentLogsOnPanic()
+
 	// Initialize the config structures of the runner and the application using default values, envirnonment variables and CLI arguments
 	ctx, logger := log.With(context.Background(), "appId", uuid.NewString())
+	ctx = context.WithValue(ctx, watchdogRegistryKey{}, ar.watchdogs)
+	ctx = context.WithValue(ctx, inFlightCounterKey{}, ar.inFlight)
 
 	if logger.Enabled(ctx, slog.LevelDebug) {
 		logger.Debug("Starting 12f application", "config", ar.config)
@@ -113,63 +168,117 @@ func (ar *ApplicationRunner) Run() error {
 	}
 	ar.wg.Add(1)
 
-	// Start the liveness and readiness check
-	hc := healthcheck.NewHealthCheck(
-		ar.wg,
-		healthcheck.Config{
-			Port: uint(ar.config.HealthCheckPort),
-			Checks: map[string]healthcheck.Check{
-				ar.config.LivenessCheckPath:  ar.livenessCheck,
-				ar.config.ReadinessCheckPath: ar.readinessCheck,
-			},
+	// Setup the admin server, that may host the health check and metrics endpoints too
+	adminServer := ar.newAdminServer(ctx)
+
+	// Start the startup, liveness and readiness check
+	healthCheckConfig := healthcheck.Config{
+		Address: ar.config.HealthCheckAddress,
+		Port:    ar.config.HealthCheckPort,
+		Checks: map[string]healthcheck.Check{
+			ar.config.StartupCheckPath:  ar.startupCheck,
+			ar.config.LivenessCheckPath: ar.livenessCheck,
+		},
+		Reports: map[string]healthcheck.ReportFunc{
+			ar.config.ReadinessCheckPath: ar.readinessCheck,
 		},
-	)
+		Timeout:             ar.config.HealthCheckTimeout,
+		Interval:            ar.config.HealthCheckInterval,
+		MaxConcurrentProbes: ar.config.HealthCheckMaxConcurrentProbes,
+	}
+	if ar.sharesAdminServer(ar.config.HealthCheckPort) {
+		healthCheckConfig.Server = adminServer
+	}
+	hc := healthcheck.NewHealthCheck(healthCheckConfig)
+	ar.healthCheck = &hc
 
 	// Setup the OTEL instrumentation
-	oti := oti.NewOtel(ar.wg, ar.config.OtelConfig)
+	otelConfig := ar.config.OtelConfig
+	if ar.prometheusExporterEnabled() && ar.sharesAdminServer(uint(otelConfig.OtelExporterPrometheusPort)) { //nolint:gosec // port is never negative
+		otelConfig.OtelExporterPrometheusPort = 0
+		adminServer.Handle(admin.MetricsPath, oti.MetricsHandler())
+	}
+	oti := oti.NewOtel(ar.wg, otelConfig)
 	ctx = oti.Startup(ctx)
+	log.SetFatalHook(oti.Shutdown)
+	otelShutdown := func(ctx context.Context) error {
+		log.SetFatalHook(nil)
+		oti.Shutdown(ctx)
+		return nil
+	}
 
 	// Start the startup process of the application to run
-	hc.Startup(ctx)
+	if err := hc.Startup(ctx, ar.wg); err != nil {
+		return ar.abortStartup(ctx, fmt.Errorf("failed to start healthcheck. %w", err), otelShutdown)
+	}
+
+	if ar.adminServerNeeded() {
+		if err := adminServer.Startup(ctx); err != nil {
+			return ar.abortStartup(ctx, fmt.Errorf("failed to start admin server. %w", err), otelShutdown, hc.Shutdown)
+		}
+	}
 
 	// Startup every component
 	if err := ar.startupComponents(ctx); err != nil {
-		return fmt.Errorf("failed to start application components: %w", err)
+		return ar.abortStartup(ctx, fmt.Errorf("failed to start application components: %w", err), otelShutdown, hc.Shutdown, adminServer.Shutdown)
 	}
 
 	if err := ar.waitUntilComponentsAreHealthy(ctx); err != nil {
-		return err
+		return ar.abortStartup(ctx, err, otelShutdown, hc.Shutdown, adminServer.Shutdown, ar.shutdownComponents)
 	}
 
 	if afterStartupHook, ok := ar.app.(AfterStartupHook); ok {
 		if err := afterStartupHook.AfterStartup(ctx, ar.wg); err != nil {
-			return fmt.Errorf("after startup hook returned error. %w", err)
+			return ar.abortStartup(ctx, fmt.Errorf("after startup hook returned error. %w", err),
+				otelShutdown, hc.Shutdown, adminServer.Shutdown, ar.shutdownComponents)
 		}
 	}
 
+	// Let the log level be changed, and the log files be reopened by signals
+	stopLevelSignals := log.NotifyLevelSignals(ctx)
+	stopReopenSignal := log.NotifyReopenSignal(ctx)
+
+	// The state is set before the graceful shutdown is set up, so a signal received meanwhile can not overwrite StateShutdown
+	ar.setState(ctx, StateRun)
+
 	// Setup graceful shutdown
 	gsd.RegisterGsdCallback(ctx, ar.wg, func(s os.Signal) {
 		defer ar.wg.Done()
+		defer log.DumpRecentLogsOnPanic()
 
 		// Shuts down the application
 		logger.Info("GsdCallback called")
+		ar.setState(ctx, StateShutdown)
+		stopLevelSignals()
+		stopReopenSignal()
+
+		// Stops receiving new requests, and lets the in-flight ones finish
+		ar.Drain(ctx)
+		ar.waitForDrain(ctx)
 
 		// Executes the BeforeShutdown hook if provided
 		if beforeShutdownHook, ok := ar.app.(BeforeShutdownHook); ok {
 			if err := beforeShutdownHook.BeforeShutdown(ctx); err != nil {
-				logger.Error("BeforeShutdown hook returned with error", "error", err)
+				logger.Error("BeforeShutdown hook returned with error", log.ErrorAttr(err))
 			}
 		}
 		// Executes the shutdown process of the application
 		if err := ar.shutdownComponents(ctx); err != nil {
-			logger.Error("Failed to shut down application", "error", err)
+			logger.Error("Failed to shut down application", log.ErrorAttr(err))
 		}
 
 		// Shut down the OTEL services
 		oti.Shutdown(ctx)
 
 		// Shut down the healthcheck services
-		hc.Shutdown(ctx)
+		if err := hc.Shutdown(ctx); err != nil {
+			logger.Error("Failed to shut down healthcheck", log.ErrorAttr(err))
+		}
+
+		// Shut down the admin server
+		if err := adminServer.Shutdown(ctx); err != nil {
+			logger.Error("Failed to shut down admin server", log.ErrorAttr(err))
+		}
 	})
 
 	// Wait until the application has shut down
@@ -177,6 +286,35 @@ func (ar *ApplicationRunner) Run() error {
 	return nil
 }
 
+// abortStartup undoes the startup steps done before the startup failed, in reverse order,
+// and returns with the error of the startup, together with the errors of the undo steps
+func (ar *ApplicationRunner) abortStartup(ctx context.Context, err error, undo ...func(ctx context.Context) error) error {
+	for _, u := range slices.Backward(undo) {
+		multierr.AppendInto(&err, u(ctx))
+	}
+	// Releases the application, that would be released by the graceful shutdown
+	ar.wg.Done()
+	return err
+}
+
+// State returns with the current lifecycle state of the application
+func (ar *ApplicationRunner) State() State {
+	return State(ar.state.Load())
+}
+
+func (ar *ApplicationRunner) setState(ctx context.Context, state State) {
+	log.InfoContext(ctx, "Application state changed", "from", ar.State(), "to", state)
+	ar.state.Store(int32(state))
+	ar.invalidateHealthCheck()
+}
+
+// invalidateHealthCheck drops the cached probe responses, so the readiness reflects the current state immediately
+func (ar *ApplicationRunner) invalidateHealthCheck() {
+	if ar.healthCheck != nil {
+		ar.healthCheck.Invalidate()
+	}
+}
+
 // Check components health
 func (ar *ApplicationRunner) waitUntilComponentsAreHealthy(ctx context.Context) error {
 	// TODO: Make this configurable?
@@ -187,21 +325,34 @@ func (ar *ApplicationRunner) waitUntilComponentsAreHealthy(ctx context.Context)
 		Build()
 
 	if err := failsafe.With(policy).Run(func() error {
-		var errs error
-		for _, c := range ar.app.Components(ctx) {
-			multierr.AppendInto(&errs, c.Check(ctx))
+		checkCtx := ctx
+		if ar.config.HealthCheckTimeout > 0 {
+			var cancel context.CancelFunc
+			checkCtx, cancel = context.WithTimeout(ctx, ar.config.HealthCheckTimeout)
+			defer cancel()
 		}
-		return errs
+		return healthcheck.Evaluate(checkCtx, ar.healthComponents(ctx, false)).Err()
 	}); err != nil {
 		return fmt.Errorf("one or more components are not healthy. %w", err)
 	}
 	return nil
 }
 
+// startupComponents starts every component. If any of them fails, the started ones are shut down in reverse order.
 func (ar *ApplicationRunner) startupComponents(ctx context.Context) error {
 	var err error
+	var started []ComponentLifecycleManager
 	for _, c := range ar.app.Components(ctx) {
-		multierr.AppendInto(&err, c.Startup(ctx, ar.wg))
+		if startupErr := c.Startup(ctx, ar.wg); startupErr != nil {
+			multierr.AppendInto(&err, startupErr)
+		} else {
+			started = append(started, c)
+		}
+	}
+	if err != nil {
+		for _, c := range slices.Backward(started) {
+			multierr.AppendInto(&err, c.Shutdown(ctx))
+		}
 	}
 	return err
 }
@@ -214,22 +365,56 @@ func (ar *ApplicationRunner) shutdownComponents(ctx context.Context) error {
 	return err
 }
 
-// livenessCheck() is the built-in livenessCheck callback function for the HealthCheck service
-func (ar *ApplicationRunner) livenessCheck(ctx context.Context) error {
-	// TODO: May add checks for heap-size, go routine num limit, etc.
-	log.DebugContext(ctx, "Liveness check")
+// startupCheck() is the built-in startupCheck callback function for the HealthCheck service.
+// It succeeds once the components have been started and the AfterStartup hook has returned.
+func (ar *ApplicationRunner) startupCheck(ctx context.Context) error {
+	if ar.State() == StateStartup {
+		return ErrStartupInProgress
+	}
 	return nil
 }
 
-// readinessCheck() is the built-in readinessCheck callback function for the HealthCheck service
-func (ar *ApplicationRunner) readinessCheck(ctx context.Context) error {
-	var err error
+// readinessCheck() is the built-in readinessCheck callback function for the HealthCheck service.
+// The application is not ready while it is starting up or shutting down, regardless of the component checks.
+func (ar *ApplicationRunner) readinessCheck(ctx context.Context) healthcheck.Report {
+	if err := ar.State().err(); err != nil {
+		log.DebugContext(ctx, "Readiness check", "state", ar.State(), log.ErrorAttr(err))
+		return healthcheck.Report{Status: healthcheck.StatusFail, Output: err.Error()}
+	}
+	if ar.Draining() {
+		log.DebugContext(ctx, "Readiness check", "draining", true)
+		return healthcheck.Report{Status: healthcheck.StatusFail, Output: ErrDraining.Error()}
+	}
+	report := healthcheck.Evaluate(ctx, ar.healthComponents(ctx, true))
+	log.DebugContext(ctx, "Readiness check", "status", report.Status, log.ErrorAttr(report.Err()))
+	return report
+}
+
+// healthComponents() collects the health checks of the components, and optionally of the application
+func (ar *ApplicationRunner) healthComponents(ctx context.Context, withApp bool) []healthcheck.Component {
+	var components []healthcheck.Component
+	names := map[string]int{}
+	add := func(c HealthCheckHook, defaultName string) {
+		name := defaultName
+		if namer, ok := c.(ComponentNamer); ok {
+			name = namer.ComponentName()
+		}
+		// Make the names unique if there are more components with the same name
+		if names[name]++; names[name] > 1 {
+			name = fmt.Sprintf("%s#%d", name, names[name])
+		}
+		critical := true
+		if criticalityHook, ok := c.(CriticalityHook); ok {
+			critical = criticalityHook.Critical()
+		}
+		components = append(components, healthcheck.Component{Name: name, Critical: critical, Check: c.Check})
+	}
+
 	for _, c := range ar.app.Components(ctx) {
-		multierr.AppendInto(&err, c.Check(ctx))
+		add(c, fmt.Sprintf("%T", c))
 	}
-	if healthCheckHook, ok := ar.app.(HealthCheckHook); ok {
-		multierr.AppendInto(&err, healthCheckHook.Check(ctx))
+	if healthCheckHook, ok := ar.app.(HealthCheckHook); ok && withApp {
+		add(healthCheckHook, "Application")
 	}
-	log.DebugContext(ctx, "Readiness check", "error", err)
-	return err
+	return components
 }
@@ -2,12 +2,19 @@ package apprun_test
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"net"
+	"net/http"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"testing"
 	"time"
 
+	client_prometheus "github.com/prometheus/client_golang/prometheus"
 	"github.com/spf13/pflag"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
@@ -87,3 +94,352 @@ func (s *AppRunnerSuite) TestStartStop() {
 	slog.Info("Wait for the threads to finish")
 	twg.Wait()
 }
+
+type SlowStartingApp struct {
+	startedCh chan any
+}
+
+func (a *SlowStartingApp) Components(ctx context.Context) []apprun.ComponentLifecycleManager {
+	return nil
+}
+
+func (a *SlowStartingApp) AfterStartup(ctx context.Context, wg *sync.WaitGroup) error {
+	<-a.startedCh
+	return nil
+}
+
+func (s *AppRunnerSuite) TestStartupAndReadinessDuringStartup() {
+	t := s.T()
+	testApp := &SlowStartingApp{startedCh: make(chan any)}
+
+	flagSet := pflag.NewFlagSet("root", pflag.ContinueOnError)
+	config := &apprun.Config{}
+	config.GetConfigFlagSet(flagSet)
+	require.NoError(t, config.LoadConfig(flagSet))
+	appRunner := apprun.NewApplicationRunner(config, testApp)
+
+	twg := &sync.WaitGroup{}
+	twg.Add(1)
+	go func() {
+		require.NoError(t, appRunner.Run())
+		twg.Done()
+	}()
+
+	require.Eventually(t, func() bool {
+		return getStatusCode("http://localhost:8080/live") == http.StatusOK
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, apprun.StateStartup, appRunner.State())
+	require.Equal(t, http.StatusServiceUnavailable, getStatusCode("http://localhost:8080/startup"))
+	require.Equal(t, http.StatusServiceUnavailable, getStatusCode("http://localhost:8080/ready"))
+
+	// Let the AfterStartup hook return
+	close(testApp.startedCh)
+	require.Eventually(t, func() bool {
+		return appRunner.State() == apprun.StateRun
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, http.StatusOK, getStatusCode("http://localhost:8080/startup"))
+	require.Equal(t, http.StatusOK, getStatusCode("http://localhost:8080/ready"))
+
+	must.Must(syscall.Kill(syscall.Getpid(), syscall.SIGTERM))
+	twg.Wait()
+}
+
+// testClient does not keep the connections open, so they do not delay the shutdown of the servers
+var testClient = &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+
+func getStatusCode(requestURL string) int {
+	res, err := testClient.Get(requestURL)
+	if err != nil {
+		return 0
+	}
+	must.Must(res.Body.Close())
+	return res.StatusCode
+}
+
+// StalledComponent registers a watchdog at startup, but never pets it
+type StalledComponent struct{}
+
+func (c *StalledComponent) Startup(ctx context.Context, wg *sync.WaitGroup) error {
+	apprun.RegisterWatchdog(ctx, "Stalled", 50*time.Millisecond)
+	return nil
+}
+
+func (c *StalledComponent) Shutdown(ctx context.Context) error { return nil }
+
+func (c *StalledComponent) Check(ctx context.Context) error { return nil }
+
+type StalledApp struct{}
+
+func (a *StalledApp) Components(ctx context.Context) []apprun.ComponentLifecycleManager {
+	return []apprun.ComponentLifecycleManager{&StalledComponent{}}
+}
+
+func (s *AppRunnerSuite) TestLivenessGuards() {
+	t := s.T()
+	testCases := map[string]struct {
+		app     apprun.Application
+		cliArgs []string
+	}{
+		"expired watchdog": {
+			app: &StalledApp{},
+		},
+		"goroutine limit exceeded": {
+			app:     NewTestApp(),
+			cliArgs: []string{"--liveness-max-goroutines=1"},
+		},
+		"heap limit exceeded": {
+			app:     NewTestApp(),
+			cliArgs: []string{"--liveness-max-heap-bytes=1"},
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			flagSet := pflag.NewFlagSet("root", pflag.ContinueOnError)
+			config := &apprun.Config{}
+			config.GetConfigFlagSet(flagSet)
+			require.NoError(t, flagSet.Parse(testCase.cliArgs))
+			require.NoError(t, config.LoadConfig(flagSet))
+			appRunner := apprun.NewApplicationRunner(config, testCase.app)
+
+			twg := &sync.WaitGroup{}
+			twg.Add(1)
+			go func() {
+				require.NoError(t, appRunner.Run())
+				twg.Done()
+			}()
+
+			require.Eventually(t, func() bool {
+				return appRunner.State() == apprun.StateRun
+			}, time.Second, 10*time.Millisecond)
+			require.Eventually(t, func() bool {
+				return getStatusCode("http://localhost:8080/live") == http.StatusServiceUnavailable
+			}, time.Second, 10*time.Millisecond)
+
+			must.Must(syscall.Kill(syscall.Getpid(), syscall.SIGTERM))
+			twg.Wait()
+		})
+	}
+}
+
+func (s *AppRunnerSuite) TestRunFailsIfPortIsTaken() {
+	t := s.T()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() { require.NoError(t, listener.Close()) }()
+	prometheusPort := freePort(t)
+	defer func(registerer client_prometheus.Registerer) { oti.DefaultPrometheusRegisterer = registerer }(oti.DefaultPrometheusRegisterer)
+	oti.DefaultPrometheusRegisterer = client_prometheus.NewRegistry()
+
+	flagSet := pflag.NewFlagSet("root", pflag.ContinueOnError)
+	config := &apprun.Config{}
+	config.GetConfigFlagSet(flagSet)
+	require.NoError(t, flagSet.Parse([]string{
+		fmt.Sprintf("--health-check-port=%d", listener.Addr().(*net.TCPAddr).Port),
+		"--otel-metrics-exporter=prometheus",
+		fmt.Sprintf("--otel-exporter-prometheus-port=%d", prometheusPort),
+	}))
+	require.NoError(t, config.LoadConfig(flagSet))
+	appRunner := apprun.NewApplicationRunner(config, NewTestApp())
+
+	require.ErrorContains(t, appRunner.Run(), "failed to start healthcheck")
+
+	// The prometheus server has been shut down
+	require.Eventually(t, func() bool { return portIsFree(prometheusPort) }, time.Second, 10*time.Millisecond)
+}
+
+// RecordingComponent records its shutdown, and fails to start up if startupErr is set
+type RecordingComponent struct {
+	startupErr error
+	shutdown   atomic.Bool
+}
+
+func (c *RecordingComponent) Startup(ctx context.Context, wg *sync.WaitGroup) error {
+	return c.startupErr
+}
+
+func (c *RecordingComponent) Shutdown(ctx context.Context) error {
+	c.shutdown.Store(true)
+	return nil
+}
+
+func (c *RecordingComponent) Check(ctx context.Context) error { return nil }
+
+type FailingApp struct {
+	components []*RecordingComponent
+}
+
+func (a *FailingApp) Components(ctx context.Context) []apprun.ComponentLifecycleManager {
+	components := []apprun.ComponentLifecycleManager{}
+	for _, c := range a.components {
+		components = append(components, c)
+	}
+	return components
+}
+
+func (s *AppRunnerSuite) TestRunFailsIfComponentFailsToStart() {
+	t := s.T()
+	started := &RecordingComponent{}
+	failed := &RecordingComponent{startupErr: errors.New("connection refused")}
+
+	flagSet := pflag.NewFlagSet("root", pflag.ContinueOnError)
+	config := &apprun.Config{}
+	config.GetConfigFlagSet(flagSet)
+	require.NoError(t, flagSet.Parse([]string{fmt.Sprintf("--health-check-port=%d", freePort(t))}))
+	require.NoError(t, config.LoadConfig(flagSet))
+	appRunner := apprun.NewApplicationRunner(config, &FailingApp{components: []*RecordingComponent{started, failed}})
+
+	require.ErrorContains(t, appRunner.Run(), "connection refused")
+	require.True(t, started.shutdown.Load())
+	require.False(t, failed.shutdown.Load())
+	require.Eventually(t, func() bool { return portIsFree(int(config.HealthCheckPort)) }, time.Second, 10*time.Millisecond)
+}
+
+func (s *AppRunnerSuite) TestRunFailsIfAdminPortIsTaken() {
+	t := s.T()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() { require.NoError(t, listener.Close()) }()
+	healthCheckPort, prometheusPort := freePort(t), freePort(t)
+	defer func(registerer client_prometheus.Registerer) { oti.DefaultPrometheusRegisterer = registerer }(oti.DefaultPrometheusRegisterer)
+	oti.DefaultPrometheusRegisterer = client_prometheus.NewRegistry()
+
+	flagSet := pflag.NewFlagSet("root", pflag.ContinueOnError)
+	config := &apprun.Config{}
+	config.GetConfigFlagSet(flagSet)
+	require.NoError(t, flagSet.Parse([]string{
+		fmt.Sprintf("--admin-port=%d", listener.Addr().(*net.TCPAddr).Port),
+		fmt.Sprintf("--health-check-port=%d", healthCheckPort),
+		"--otel-metrics-exporter=prometheus",
+		fmt.Sprintf("--otel-exporter-prometheus-port=%d", prometheusPort),
+	}))
+	require.NoError(t, config.LoadConfig(flagSet))
+	appRunner := apprun.NewApplicationRunner(config, NewTestApp())
+
+	require.ErrorContains(t, appRunner.Run(), "failed to start admin server")
+
+	// The healthcheck and the prometheus servers have been shut down
+	for _, port := range []int{healthCheckPort, prometheusPort} {
+		require.Eventually(t, func() bool { return portIsFree(port) }, time.Second, 10*time.Millisecond, port)
+	}
+}
+
+// portIsFree tells whether the TCP port can be listened on
+func portIsFree(port int) bool {
+	l, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return false
+	}
+	return l.Close() == nil
+}
+
+// freePort returns with a TCP port, that is not in use
+func freePort(t *testing.T) int {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() { require.NoError(t, l.Close()) }()
+	return l.Addr().(*net.TCPAddr).Port
+}
+
+// DrainingApp tracks an in-flight request from the end of the startup until it is released
+type DrainingApp struct {
+	release        func()
+	beforeShutdown atomic.Bool
+}
+
+func (a *DrainingApp) Components(ctx context.Context) []apprun.ComponentLifecycleManager {
+	return nil
+}
+
+func (a *DrainingApp) AfterStartup(ctx context.Context, wg *sync.WaitGroup) error {
+	a.release = apprun.TrackInFlight(ctx)
+	return nil
+}
+
+func (a *DrainingApp) BeforeShutdown(ctx context.Context) error {
+	a.beforeShutdown.Store(true)
+	return nil
+}
+
+func (s *AppRunnerSuite) TestDrain() {
+	t := s.T()
+	testApp := &DrainingApp{}
+
+	flagSet := pflag.NewFlagSet("root", pflag.ContinueOnError)
+	config := &apprun.Config{}
+	config.GetConfigFlagSet(flagSet)
+	// The cached probe responses are invalidated by the state and drain mode changes, so they are not stale
+	require.NoError(t, flagSet.Parse([]string{"--admin-routes=drain", "--shutdown-drain-delay=10s", "--shutdown-drain-min-delay=100ms",
+		"--health-check-interval=1h"}))
+	require.NoError(t, config.LoadConfig(flagSet))
+	appRunner := apprun.NewApplicationRunner(config, testApp)
+
+	twg := &sync.WaitGroup{}
+	twg.Add(1)
+	go func() {
+		require.NoError(t, appRunner.Run())
+		twg.Done()
+	}()
+	require.Eventually(t, func() bool {
+		return appRunner.State() == apprun.StateRun
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, http.StatusOK, getStatusCode("http://localhost:8080/ready"))
+
+	// Enter, then leave the drain mode manually
+	require.Equal(t, http.StatusOK, putJSON(t, "http://localhost:8080/drain", `{"draining": true}`))
+	require.True(t, appRunner.Draining())
+	require.Equal(t, http.StatusServiceUnavailable, getStatusCode("http://localhost:8080/ready"))
+	require.Equal(t, http.StatusOK, putJSON(t, "http://localhost:8080/drain", `{"draining": false}`))
+	require.Equal(t, http.StatusOK, getStatusCode("http://localhost:8080/ready"))
+
+	// The shutdown waits for the in-flight request instead of the whole drain delay
+	started := time.Now()
+	must.Must(syscall.Kill(syscall.Getpid(), syscall.SIGTERM))
+	require.Eventually(t, appRunner.Draining, time.Second, 10*time.Millisecond)
+	require.Equal(t, http.StatusServiceUnavailable, getStatusCode("http://localhost:8080/ready"))
+	require.False(t, testApp.beforeShutdown.Load())
+
+	testApp.release()
+	twg.Wait()
+	require.True(t, testApp.beforeShutdown.Load())
+	require.Less(t, time.Since(started), 5*time.Second)
+}
+
+func (s *AppRunnerSuite) TestDrainWithoutInFlightRequests() {
+	t := s.T()
+
+	flagSet := pflag.NewFlagSet("root", pflag.ContinueOnError)
+	config := &apprun.Config{}
+	config.GetConfigFlagSet(flagSet)
+	require.NoError(t, flagSet.Parse([]string{"--shutdown-drain-delay=10s", "--shutdown-drain-min-delay=500ms"}))
+	require.NoError(t, config.LoadConfig(flagSet))
+	appRunner := apprun.NewApplicationRunner(config, NewTestApp())
+
+	twg := &sync.WaitGroup{}
+	twg.Add(1)
+	go func() {
+		require.NoError(t, appRunner.Run())
+		twg.Done()
+	}()
+	require.Eventually(t, func() bool {
+		return appRunner.State() == apprun.StateRun
+	}, time.Second, 10*time.Millisecond)
+
+	// The shutdown waits for the min delay, while the readiness check fails
+	started := time.Now()
+	must.Must(syscall.Kill(syscall.Getpid(), syscall.SIGTERM))
+	require.Eventually(t, appRunner.Draining, time.Second, 10*time.Millisecond)
+	require.Equal(t, http.StatusServiceUnavailable, getStatusCode("http://localhost:8080/ready"))
+
+	twg.Wait()
+	require.GreaterOrEqual(t, time.Since(started), 500*time.Millisecond)
+	require.Less(t, time.Since(started), 5*time.Second)
+}
+
+func putJSON(t *testing.T, url string, body string) int {
+	req, err := http.NewRequest(http.MethodPut, url, strings.NewReader(body))
+	require.NoError(t, err)
+	res, err := testClient.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, res.Body.Close())
+	return res.StatusCode
+}
@@ -1,30 +1,107 @@
 package apprun
 
 import (
+	"time"
+
 	"github.com/spf13/pflag"
+	"github.com/tombenke/go-12f-common/v2/admin"
 	"github.com/tombenke/go-12f-common/v2/config"
+	"github.com/tombenke/go-12f-common/v2/log"
 	"github.com/tombenke/go-12f-common/v2/oti"
 )
 
 const (
-	LogLevelDefault  = "info"
-	LogFormatDefault = "json"
+	LogLevelDefault            = "info"
+	LogFormatDefault           = log.FormatAuto
+	LogLevelRevertAfterDefault = 0
+	LogOutputDefault           = log.OutputStderr
+
+	LogFileMaxSizeDefault        = 0
+	LogFileRotateIntervalDefault = 0
+	LogFileMaxAgeDefault         = 0
+	LogFileMaxBackupsDefault     = 0
+	LogFileCompressDefault       = false
+
+	LogSamplingIntervalDefault   = 0
+	LogSamplingFirstDefault      = 100
+	LogSamplingThereafterDefault = 100
+
+	LogBufferSizeDefault     = 0
+	LogBufferWarnSizeDefault = 100
+	LogBufferLevelDefault    = "debug"
 
 	HealthCheckPortDefault    = 8080
+	HealthCheckAddressDefault = ""
+	StartupCheckPathDefault   = "/startup"
 	LivenessCheckPathDefault  = "/live"
 	ReadinessCheckPathDefault = "/ready"
+
+	HealthCheckTimeoutDefault             = time.Second
+	HealthCheckIntervalDefault            = 0
+	HealthCheckMaxConcurrentProbesDefault = 0
+
+	LivenessMaxHeapBytesDefault    = 0
+	LivenessMaxGoroutinesDefault   = 0
+	LivenessMaxGCPauseDefault      = 0
+	LivenessWatchdogTimeoutDefault = 5 * time.Minute
+
+	ShutdownDrainDelayDefault    = 0
+	ShutdownDrainMinDelayDefault = 5 * time.Second
+
+	AdminPortDefault    = HealthCheckPortDefault
+	AdminAddressDefault = ""
 )
 
 // Config represents the main configuration object of the 12-factor application instance
 // It holds those parameters that are needed to setup the basic functionalities of the application,
-// e.g. logging, healthcheck, levness and readiness checks.
+// e.g. logging, healthcheck, startup, levness and readiness checks.
 type Config struct {
-	LogLevel           string `mapstructure:"log-level"`
-	LogFormat          string `mapstructure:"log-format"`
-	HealthCheckPort    uint   `mapstructure:"health-check-port"`
-	LivenessCheckPath  string `mapstructure:"liveness-check-path"`
-	ReadinessCheckPath string `mapstructure:"readiness-check-path"`
-	OtelConfig         oti.Config
+	LogLevel            string        `mapstructure:"log-level"`
+	LogFormat           string        `mapstructure:"log-format"`
+	LogLevels           []string      `mapstructure:"log-levels"`
+	LogLevelRevertAfter time.Duration `mapstructure:"log-level-revert-after"`
+	LogOutputs          []string      `mapstructure:"log-output"`
+	HealthCheckPort     uint          `mapstructure:"health-check-port"`
+	HealthCheckAddress  string        `mapstructure:"health-check-address"`
+	StartupCheckPath    string        `mapstructure:"startup-check-path"`
+	LivenessCheckPath   string        `mapstructure:"liveness-check-path"`
+	ReadinessCheckPath  string        `mapstructure:"readiness-check-path"`
+
+	LogFileMaxSize        uint          `mapstructure:"log-file-max-size"`
+	LogFileRotateInterval time.Duration `mapstructure:"log-file-rotate-interval"`
+	LogFileMaxAge         time.Duration `mapstructure:"log-file-max-age"`
+	LogFileMaxBackups     int           `mapstructure:"log-file-max-backups"`
+	LogFileCompress       bool          `mapstructure:"log-file-compress"`
+
+	LogSamplingInterval   time.Duration `mapstructure:"log-sampling-interval"`
+	LogSamplingFirst      int           `mapstructure:"log-sampling-first"`
+	LogSamplingThereafter int           `mapstructure:"log-sampling-thereafter"`
+
+	LogBufferSize     int    `mapstructure:"log-buffer-size"`
+	LogBufferWarnSize int    `mapstructure:"log-buffer-warn-size"`
+	LogBufferLevel    string `mapstructure:"log-buffer-level"`
+
+	HealthCheckTimeout             time.Duration `mapstructure:"health-check-timeout"`
+	HealthCheckInterval            time.Duration `mapstructure:"health-check-interval"`
+	HealthCheckMaxConcurrentProbes int           `mapstructure:"health-check-max-concurrent-probes"`
+
+	LivenessMaxHeapBytes    uint64        `mapstructure:"liveness-max-heap-bytes"`
+	LivenessMaxGoroutines   int           `mapstructure:"liveness-max-goroutines"`
+	LivenessMaxGCPause      time.Duration `mapstructure:"liveness-max-gc-pause"`
+	LivenessWatchdogTimeout time.Duration `mapstructure:"liveness-watchdog-timeout"`
+
+	ShutdownDrainDelay    time.Duration `mapstructure:"shutdown-drain-delay"`
+	ShutdownDrainMinDelay time.Duration `mapstructure:"shutdown-drain-min-delay"`
+
+	AdminPort        uint     `mapstructure:"admin-port"`
+	AdminAddress     string   `mapstructure:"admin-address"`
+	AdminUsername    string   `mapstructure:"admin-username"`
+	AdminPassword    string   `mapstructure:"admin-password" log:"redact"`
+	AdminTLSCertFile string   `mapstructure:"admin-tls-cert-file"`
+	AdminTLSKeyFile  string   `mapstructure:"admin-tls-key-file"`
+	AdminRoutes      []string `mapstructure:"admin-routes"`
+
+	OtelConfig oti.Config
 }
 
 // GetConfigFlagSet() initializes the configuration object of the 12-factor application, and returns with it
@@ -35,12 +112,119 @@ func (cfg *Config) GetConfigFlagSet(flagSet *pflag.FlagSet) {
 		LogLevelDefault,
 		"The log level: panic | fatal | error | warning | info | debug | trace",
 	)
-	flagSet.StringP("log-format", "f", LogFormatDefault, "The log format: json | text")
+	flagSet.StringP("log-format", "f", LogFormatDefault, "The log format: json | text | console | auto. The auto format is console if the output is a terminal, otherwise json")
+	flagSet.StringSlice(
+		"log-levels",
+		[]string{},
+		"The log levels of the components that override the log-level, e.g. Worker=debug,HealthCheck=warn",
+	)
+	flagSet.Duration(
+		"log-level-revert-after",
+		LogLevelRevertAfterDefault,
+		"The duration after which the log level changed at runtime reverts to the log-level. No revert if 0",
+	)
+	flagSet.StringSlice(
+		"log-output",
+		[]string{LogOutputDefault},
+		"The outputs of the logs: stderr | stdout | file:<path>, optionally prefixed by the format, e.g. json:file:/var/log/app.log,text:stderr",
+	)
+
+	// Log file rotation parameters
+	flagSet.Uint("log-file-max-size", LogFileMaxSizeDefault, "The size in megabytes, above which the log files are rotated. No size based rotation if 0")
+	flagSet.Duration("log-file-rotate-interval", LogFileRotateIntervalDefault, "The age of the log files, after which they are rotated. No time based rotation if 0")
+	flagSet.Duration("log-file-max-age", LogFileMaxAgeDefault, "The age of the rotated log files, after which they are removed. No removal by age if 0")
+	flagSet.Int("log-file-max-backups", LogFileMaxBackupsDefault, "The number of the rotated log files to keep. All of them are kept if 0")
+	flagSet.Bool("log-file-compress", LogFileCompressDefault, "Compress the rotated log files with gzip")
+
+	// Log sampling parameters
+	flagSet.Duration(
+		"log-sampling-interval",
+		LogSamplingIntervalDefault,
+		"The interval of sampling the log records with the same level and message. No sampling if 0",
+	)
+	flagSet.Int("log-sampling-first", LogSamplingFirstDefault, "The number of the log records with the same level and message, that pass within a sampling interval")
+	flagSet.Int(
+		"log-sampling-thereafter",
+		LogSamplingThereafterDefault,
+		"Every n-th log record passes after the first ones within a sampling interval. The rest is dropped if 0",
+	)
+
+	// Recent logs parameters
+	flagSet.Int(
+		"log-buffer-size",
+		LogBufferSizeDefault,
+		"The number of the recent log records kept in memory, served by the logs admin route and dumped to stderr on fatal errors. Disabled if 0",
+	)
+	flagSet.Int(
+		"log-buffer-warn-size",
+		LogBufferWarnSizeDefault,
+		"The number of the recent warning and higher level log records kept in memory besides the log-buffer-size lower level ones. "+
+			"They share the log-buffer-size records if 0",
+	)
+	flagSet.String(
+		"log-buffer-level",
+		LogBufferLevelDefault,
+		"The level of the recent log records kept in memory, regardless of the log-level: panic | fatal | error | warning | info | debug | trace",
+	)
 
 	// HealthCheck parameters
 	flagSet.Uint("health-check-port", HealthCheckPortDefault, "The HTTP port of the healthcheck endpoints")
+	flagSet.String("health-check-address", HealthCheckAddressDefault, "The bind address of the healthcheck endpoints. Binds to all interfaces if empty")
+	flagSet.String("startup-check-path", StartupCheckPathDefault, "The path of the startup check endpoint")
 	flagSet.String("liveness-check-path", LivenessCheckPathDefault, "The path of the liveness check endpoint")
 	flagSet.String("readiness-check-path", ReadinessCheckPathDefault, "The path of the readiness check endpoint")
+	flagSet.Duration("health-check-timeout", HealthCheckTimeoutDefault, "The timeout of the health checks of an endpoint. No timeout if 0")
+	flagSet.Duration(
+		"health-check-interval",
+		HealthCheckIntervalDefault,
+		"The interval of running the health checks in the background. If 0, the checks run on each probe, otherwise the endpoints serve the cached results",
+	)
+	flagSet.Int(
+		"health-check-max-concurrent-probes",
+		HealthCheckMaxConcurrentProbesDefault,
+		"The max number of concurrent probes per endpoint. The probes over the limit are rejected. No limit if 0",
+	)
+
+	// Liveness guards
+	flagSet.Uint64("liveness-max-heap-bytes", LivenessMaxHeapBytesDefault, "The liveness check fails if the heap size exceeds this limit. No limit if 0")
+	flagSet.Int("liveness-max-goroutines", LivenessMaxGoroutinesDefault, "The liveness check fails if the number of goroutines exceeds this limit. No limit if 0")
+	flagSet.Duration("liveness-max-gc-pause", LivenessMaxGCPauseDefault, "The liveness check fails if the last GC pause exceeds this limit. No limit if 0")
+	flagSet.Duration(
+		"liveness-watchdog-timeout",
+		LivenessWatchdogTimeoutDefault,
+		"The default timeout of the watchdogs. The liveness check fails if a watchdog has not been petted within its timeout",
+	)
+
+	// Shutdown parameters
+	flagSet.Duration(
+		"shutdown-drain-delay",
+		ShutdownDrainDelayDefault,
+		"The max time to wait after the readiness check started to fail at shutdown, before the components are shut down. "+
+			"The wait ends earlier once the requests tracked as in-flight have all finished, but not before the shutdown-drain-min-delay",
+	)
+	flagSet.Duration(
+		"shutdown-drain-min-delay",
+		ShutdownDrainMinDelayDefault,
+		"The min time to wait after the readiness check started to fail at shutdown, so the load balancers can remove the application from their endpoints. "+
+			"It is limited by the shutdown-drain-delay",
+	)
+
+	// Admin server parameters
+	flagSet.Uint(
+		"admin-port",
+		AdminPortDefault,
+		"The HTTP port of the admin endpoints. If it equals to the health-check-port or the otel-exporter-prometheus-port, the endpoints share the same server",
+	)
+	flagSet.String("admin-address", AdminAddressDefault, "The bind address of the admin server. Binds to all interfaces if empty")
+	flagSet.String("admin-username", "", "The username of the basic authentication of the admin endpoints, except the health checks")
+	flagSet.String("admin-password", "", "The password of the basic authentication of the admin endpoints, except the health checks")
+	flagSet.String("admin-tls-cert-file", "", "The TLS certificate file of the admin server. TLS is enabled if both the cert and key files are set, setting only one of them is an error")
+	flagSet.String("admin-tls-key-file", "", "The TLS key file of the admin server. TLS is enabled if both the cert and key files are set, setting only one of them is an error")
+	flagSet.StringSlice(
+		"admin-routes",
+		[]string{admin.RouteVersion},
+		"The routes served by the admin server: version | config | pprof | loglevel | logs | drain",
+	)
 
 	cfg.OtelConfig.GetConfigFlagSet(flagSet)
 }
@@ -52,5 +236,16 @@ func (cfg *Config) LoadConfig(flagSet *pflag.FlagSet) error {
 	return cfg.OtelConfig.LoadConfig(flagSet)
 }
 
+// logRotation returns with the rotation config of the log files
+func (cfg *Config) logRotation() log.RotationConfig {
+	return log.RotationConfig{
+		MaxSize:    int64(cfg.LogFileMaxSize) * 1024 * 1024, //nolint:gosec // the size in megabytes never overflows
+		Interval:   cfg.LogFileRotateInterval,
+		MaxAge:     cfg.LogFileMaxAge,
+		MaxBackups: cfg.LogFileMaxBackups,
+		Compress:   cfg.LogFileCompress,
+	}
+}
+
 // Ensure that Config implements the Configurer interface
 var _ config.Configurer = (*Config)(nil)
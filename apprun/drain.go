@@ -0,0 +1,97 @@
+package apprun
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/tombenke/go-12f-common/v2/log"
+)
+
+// drainPollInterval is the interval of checking the in-flight counter during the drain phase
+const drainPollInterval = 10 * time.Millisecond
+
+// ErrDraining is reported by the readiness check while the application is in drain mode
+var ErrDraining = errors.New("application is draining")
+
+// inFlightCounter counts the requests tracked by TrackInFlight
+type inFlightCounter struct {
+	count atomic.Int64
+}
+
+type inFlightCounterKey struct{}
+
+// drained tells whether the tracked requests have all finished
+func (c *inFlightCounter) drained() bool {
+	return c.count.Load() == 0
+}
+
+// TrackInFlight counts a request as in-flight until the returned function is called.
+// The drain phase of the shutdown ends before the `shutdown-drain-delay` once there is no in-flight request,
+// but not before the `shutdown-drain-min-delay`.
+// If ctx does not come from an application runner, the request is not counted.
+func TrackInFlight(ctx context.Context) (done func()) {
+	counter, ok := ctx.Value(inFlightCounterKey{}).(*inFlightCounter)
+	if !ok {
+		return func() {}
+	}
+	counter.count.Add(1)
+	var once sync.Once
+	return func() {
+		once.Do(func() { counter.count.Add(-1) })
+	}
+}
+
+// Drain puts the application into drain mode, so the readiness check fails,
+// and the load balancers stop sending new requests to the application.
+func (ar *ApplicationRunner) Drain(ctx context.Context) {
+	if !ar.draining.Swap(true) {
+		log.InfoContext(ctx, "Entering drain mode", "inFlight", ar.inFlight.count.Load())
+		ar.invalidateHealthCheck()
+	}
+}
+
+// Resume takes the application out of drain mode
+func (ar *ApplicationRunner) Resume(ctx context.Context) {
+	if ar.draining.Swap(false) {
+		log.InfoContext(ctx, "Leaving drain mode")
+		ar.invalidateHealthCheck()
+	}
+}
+
+// Draining tells whether the application is in drain mode
+func (ar *ApplicationRunner) Draining() bool {
+	return ar.draining.Load()
+}
+
+// waitForDrain waits until the `shutdown-drain-delay` elapses, or the tracked in-flight requests have all finished.
+// It waits at least the `shutdown-drain-min-delay`, so the load balancers can remove the application from their endpoints,
+// even if there is no in-flight request.
+func (ar *ApplicationRunner) waitForDrain(ctx context.Context) {
+	delay := ar.config.ShutdownDrainDelay
+	if delay <= 0 {
+		return
+	}
+	minDelay := min(max(ar.config.ShutdownDrainMinDelay, 0), delay)
+	log.InfoContext(ctx, "Waiting for drain", "delay", delay, "minDelay", minDelay, "inFlight", ar.inFlight.count.Load())
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	minTimer := time.NewTimer(minDelay)
+	defer minTimer.Stop()
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+	for minElapsed := false; !minElapsed || !ar.inFlight.drained(); {
+		select {
+		case <-timer.C:
+			log.InfoContext(ctx, "Drain delay elapsed", "inFlight", ar.inFlight.count.Load())
+			return
+		case <-minTimer.C:
+			minElapsed = true
+		case <-ticker.C:
+		}
+	}
+	log.InfoContext(ctx, "In-flight requests have finished")
+}
@@ -0,0 +1,139 @@
+package apprun
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"runtime/metrics"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/tombenke/go-12f-common/v2/log"
+	"go.uber.org/multierr"
+)
+
+const (
+	metricHeapBytes  = "/memory/classes/heap/objects:bytes"
+	metricGoroutines = "/sched/goroutines:goroutines"
+)
+
+var (
+	// ErrHeapLimitExceeded is reported by the liveness check if the heap size exceeds the limit
+	ErrHeapLimitExceeded = errors.New("heap size limit exceeded")
+	// ErrGoroutineLimitExceeded is reported by the liveness check if the number of goroutines exceeds the limit
+	ErrGoroutineLimitExceeded = errors.New("goroutine number limit exceeded")
+	// ErrGCPauseLimitExceeded is reported by the liveness check if the last GC pause exceeds the limit
+	ErrGCPauseLimitExceeded = errors.New("GC pause limit exceeded")
+	// ErrWatchdogExpired is reported by the liveness check if a watchdog has not been petted within its timeout
+	ErrWatchdogExpired = errors.New("watchdog expired")
+)
+
+// Watchdog detects stalled loops. The component should Pet() it in every iteration of its loop,
+// otherwise the liveness check fails after the timeout of the watchdog.
+type Watchdog struct {
+	name     string
+	timeout  time.Duration
+	lastPet  atomic.Int64
+	registry *watchdogRegistry
+}
+
+// Pet signals that the loop watched by the watchdog is alive
+func (w *Watchdog) Pet() {
+	w.lastPet.Store(time.Now().UnixNano())
+}
+
+// Stop unregisters the watchdog, e.g. when the watched loop exits during shutdown
+func (w *Watchdog) Stop() {
+	if w.registry != nil {
+		w.registry.unregister(w)
+	}
+}
+
+// check returns with error if the watchdog has not been petted within its timeout
+func (w *Watchdog) check(now time.Time) error {
+	if elapsed := now.Sub(time.Unix(0, w.lastPet.Load())); elapsed > w.timeout {
+		return fmt.Errorf("%w: %s has not been petted for %s", ErrWatchdogExpired, w.name, elapsed.Round(time.Millisecond))
+	}
+	return nil
+}
+
+// watchdogRegistry holds the watchdogs that the liveness check verifies
+type watchdogRegistry struct {
+	mu             sync.Mutex
+	watchdogs      map[*Watchdog]struct{}
+	defaultTimeout time.Duration
+}
+
+type watchdogRegistryKey struct{}
+
+func newWatchdogRegistry(defaultTimeout time.Duration) *watchdogRegistry {
+	return &watchdogRegistry{watchdogs: map[*Watchdog]struct{}{}, defaultTimeout: defaultTimeout}
+}
+
+func (r *watchdogRegistry) unregister(w *Watchdog) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.watchdogs, w)
+}
+
+// check returns with the errors of the expired watchdogs
+func (r *watchdogRegistry) check() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var err error
+	now := time.Now()
+	for w := range r.watchdogs {
+		multierr.AppendInto(&err, w.check(now))
+	}
+	return err
+}
+
+// RegisterWatchdog creates a petted watchdog, and registers it to the liveness check of the application runner,
+// that passed ctx to the Startup() of the component. If the timeout is not positive, the `liveness-watchdog-timeout`
+// config parameter is used. If ctx does not come from an application runner, the watchdog is not checked.
+func RegisterWatchdog(ctx context.Context, name string, timeout time.Duration) *Watchdog {
+	w := &Watchdog{name: name, timeout: timeout}
+	w.Pet()
+	if registry, ok := ctx.Value(watchdogRegistryKey{}).(*watchdogRegistry); ok {
+		if w.timeout <= 0 {
+			w.timeout = registry.defaultTimeout
+		}
+		w.registry = registry
+		registry.mu.Lock()
+		defer registry.mu.Unlock()
+		registry.watchdogs[w] = struct{}{}
+	}
+	return w
+}
+
+// livenessCheck() is the built-in livenessCheck callback function for the HealthCheck service.
+// It checks the runtime guards that are enabled by the config, and the registered watchdogs.
+func (ar *ApplicationRunner) livenessCheck(ctx context.Context) error {
+	var err error
+	samples := []metrics.Sample{{Name: metricHeapBytes}, {Name: metricGoroutines}}
+	metrics.Read(samples)
+
+	if limit := ar.config.LivenessMaxHeapBytes; limit > 0 {
+		if heap := samples[0].Value.Uint64(); heap > limit {
+			multierr.AppendInto(&err, fmt.Errorf("%w: %d bytes, limit: %d", ErrHeapLimitExceeded, heap, limit))
+		}
+	}
+	if limit := ar.config.LivenessMaxGoroutines; limit > 0 {
+		if goroutines := samples[1].Value.Uint64(); goroutines > uint64(limit) {
+			multierr.AppendInto(&err, fmt.Errorf("%w: %d, limit: %d", ErrGoroutineLimitExceeded, goroutines, limit))
+		}
+	}
+	if limit := ar.config.LivenessMaxGCPause; limit > 0 {
+		var stats debug.GCStats
+		debug.ReadGCStats(&stats)
+		if len(stats.Pause) > 0 && stats.Pause[0] > limit {
+			multierr.AppendInto(&err, fmt.Errorf("%w: %s, limit: %s", ErrGCPauseLimitExceeded, stats.Pause[0], limit))
+		}
+	}
+	multierr.AppendInto(&err, ar.watchdogs.check())
+
+	log.DebugContext(ctx, "Liveness check", "heap", samples[0].Value.Uint64(), "goroutines", samples[1].Value.Uint64(), log.ErrorAttr(err))
+	return err
+}
@@ -0,0 +1,47 @@
+package apprun
+
+import "errors"
+
+// State is the lifecycle state of the application
+type State int32
+
+const (
+	// StateStartup is the state while the components are starting up, until the AfterStartup hook returns
+	StateStartup State = iota
+	// StateRun is the state while the application is running normally
+	StateRun
+	// StateShutdown is the state after the application got the signal to shut down
+	StateShutdown
+)
+
+var (
+	// ErrStartupInProgress is reported by the startup and readiness checks while the application is starting up
+	ErrStartupInProgress = errors.New("application is starting up")
+	// ErrShutdownInProgress is reported by the readiness check while the application is shutting down
+	ErrShutdownInProgress = errors.New("application is shutting down")
+)
+
+func (s State) String() string {
+	switch s {
+	case StateStartup:
+		return "STARTUP"
+	case StateRun:
+		return "RUN"
+	case StateShutdown:
+		return "SHUTDOWN"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// err returns with the error that the readiness check reports in the state, or nil if the application may be ready
+func (s State) err() error {
+	switch s {
+	case StateRun:
+		return nil
+	case StateStartup:
+		return ErrStartupInProgress
+	default:
+		return ErrShutdownInProgress
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/tombenke/go-12f-common/v2/apprun"
 	"github.com/tombenke/go-12f-common/v2/examples/scheduler/model"
 	"github.com/tombenke/go-12f-common/v2/healthcheck"
 	"github.com/tombenke/go-12f-common/v2/log"
@@ -20,6 +21,7 @@ type Timer struct {
 	doneCh        chan interface{}
 	currentTimeCh chan model.TimerRequest
 	ticker        *time.Ticker
+	watchdog      *apprun.Watchdog
 }
 
 // Create a new Timer instance
@@ -42,6 +44,9 @@ func (t *Timer) Startup(ctx context.Context, wg *sync.WaitGroup) error {
 
 	logger.Debug("Starting ticker", "duration", tickerDuration.String())
 	t.ticker = time.NewTicker(tickerDuration)
+
+	// The ticks are sent synchronously, so the watchdog also expires if the Worker stalls
+	t.watchdog = apprun.RegisterWatchdog(ctx, t.ComponentName(), 2*tickerDuration)
 	go t.run(ctx)
 	return nil
 }
@@ -63,6 +68,7 @@ func (t *Timer) run(ctx context.Context) {
 	logger := log.GetFromContextOrDefault(ctx)
 	defer t.appWg.Done()
 	defer logger.Debug("Stopped")
+	defer t.watchdog.Stop()
 
 	// The component is working properly
 	t.err = nil
@@ -73,6 +79,7 @@ func (t *Timer) run(ctx context.Context) {
 		case currentTime := <-t.ticker.C:
 			logger.Debug("Tick", "currentTime", currentTime)
 			t.currentTimeCh <- model.TimerRequest{Ctx: ctx, CurrentTime: currentTime}
+			t.watchdog.Pet()
 			continue
 
 		// Catch the shutdown signal
@@ -91,5 +98,9 @@ func (t *Timer) Check(ctx context.Context) error {
 }
 
 func (t *Timer) getLogger(ctx context.Context) (context.Context, *slog.Logger) {
-	return log.With(ctx, "component", "Timer")
+	return log.With(ctx, "component", t.ComponentName())
+}
+
+func (t *Timer) ComponentName() string {
+	return "Timer"
 }
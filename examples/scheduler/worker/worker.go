@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/spf13/pflag"
+	"github.com/tombenke/go-12f-common/v2/apprun"
 	"github.com/tombenke/go-12f-common/v2/healthcheck"
 	"github.com/tombenke/go-12f-common/v2/log"
 	"github.com/tombenke/go-12f-common/v2/oti"
@@ -33,6 +34,7 @@ type Worker struct {
 	doneCh        chan any
 	currentTimeCh chan model.TimerRequest
 	runCount      metric_api.Int64Counter
+	watchdog      *apprun.Watchdog
 }
 
 // Create a new Worker instance
@@ -67,11 +69,12 @@ func (t *Worker) Startup(ctx context.Context, wg *sync.WaitGroup) error {
 	)
 	if err != nil {
 		logger.Error("failed runCount meter creation",
-			oti.KeyError, err, oti.KeyMetricName, RunCountName)
+			log.ErrorAttr(err), oti.KeyMetricName, RunCountName)
 		panic(err)
 	}
 
 	// Run the worker
+	t.watchdog = apprun.RegisterWatchdog(ctx, t.ComponentName(), 0)
 	go t.run(ctx)
 
 	return nil
@@ -94,6 +97,7 @@ func (t *Worker) run(ctx context.Context) {
 	_, logger := t.getLogger(ctx)
 	defer t.appWg.Done()
 	defer logger.Debug("Stopped")
+	defer t.watchdog.Stop()
 
 	// The component is working properly
 	t.err = nil
@@ -102,11 +106,12 @@ func (t *Worker) run(ctx context.Context) {
 		select {
 		case currentTime := <-t.currentTimeCh:
 			logger.Debug("Tick", "current.time", currentTime.CurrentTime)
+			t.watchdog.Pet()
 			t.runCount.Add(ctx, 1)
 			if _, err := obsProcessTimerRequest(
 				t, t.processTimerRequest,
 			)(ctx, currentTime); err != nil {
-				logger.Error("Error processing timer request", "err", err)
+				logger.Error("Error processing timer request", log.ErrorAttr(err))
 			}
 		case <-t.doneCh:
 			logger.Debug("Shutting down")
@@ -8,11 +8,12 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/tombenke/go-12f-common/v2/log"
 )
 
 func TestRegisterByChannel(t *testing.T) {
-	log.SetupDefault("debug", "text")
+	require.NoError(t, log.SetupDefault("debug", "text"))
 	var mu sync.Mutex
 	gsdCbCalled := false
 
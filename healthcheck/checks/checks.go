@@ -0,0 +1,99 @@
+// Package checks provides reusable health checks of the common backends,
+// that the components can use in their Check() methods, or as healthcheck.Component checks.
+//
+// The checks that call a backend limit their duration by the given timeout (no limit if zero) in addition to the deadline of the context.
+// NATSConnection, FileExists and DiskFree take no timeout: the first only reads the connection status, the others call the local file system,
+// and a hanging file system call is abandoned by healthcheck.Evaluate when the context is done.
+// Every check returns with an error in the "<kind> check of <target> failed. <cause>" form.
+package checks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/tombenke/go-12f-common/v2/healthcheck"
+)
+
+var (
+	// ErrUnexpectedStatus is returned by the HTTP check if the response status code differs from the expected one
+	ErrUnexpectedStatus = errors.New("unexpected status code")
+	// ErrNoAddress is returned by the DNS check if the name resolves to no address
+	ErrNoAddress = errors.New("no address found")
+)
+
+// withTimeout derives a context with the timeout, if it is greater than zero
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout > 0 {
+		return context.WithTimeout(ctx, timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// checkError wraps the cause of a failed check
+func checkError(kind string, target string, err error) error {
+	return fmt.Errorf("%s check of %s failed. %w", kind, target, err)
+}
+
+// TCP returns a check that dials the TCP address, e.g. "localhost:5432"
+func TCP(address string, timeout time.Duration) healthcheck.Check {
+	return func(ctx context.Context) error {
+		ctx, cancel := withTimeout(ctx, timeout)
+		defer cancel()
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "tcp", address)
+		if err != nil {
+			return checkError("tcp", address, err)
+		}
+		if err := conn.Close(); err != nil {
+			return checkError("tcp", address, err)
+		}
+		return nil
+	}
+}
+
+// HTTPGet returns a check that sends a GET request to the URL, and expects a response with the given status code.
+// If client is nil, http.DefaultClient is used.
+func HTTPGet(client *http.Client, url string, expectedStatus int, timeout time.Duration) healthcheck.Check {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return func(ctx context.Context) error {
+		ctx, cancel := withTimeout(ctx, timeout)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return checkError("http", url, err)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return checkError("http", url, err)
+		}
+		if err := resp.Body.Close(); err != nil {
+			return checkError("http", url, err)
+		}
+		if resp.StatusCode != expectedStatus {
+			return checkError("http", url, fmt.Errorf("%w: %d, expected: %d", ErrUnexpectedStatus, resp.StatusCode, expectedStatus))
+		}
+		return nil
+	}
+}
+
+// DNS returns a check that resolves the host name to at least one address
+func DNS(host string, timeout time.Duration) healthcheck.Check {
+	return func(ctx context.Context) error {
+		ctx, cancel := withTimeout(ctx, timeout)
+		defer cancel()
+		addrs, err := net.DefaultResolver.LookupHost(ctx, host)
+		if err != nil {
+			return checkError("dns", host, err)
+		}
+		if len(addrs) == 0 {
+			return checkError("dns", host, ErrNoAddress)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,105 @@
+package checks_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tombenke/go-12f-common/v2/healthcheck/checks"
+)
+
+func TestTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := listener.Addr().String()
+
+	assert.NoError(t, checks.TCP(address, time.Second)(context.Background()))
+
+	require.NoError(t, listener.Close())
+	err = checks.TCP(address, time.Second)(context.Background())
+	assert.ErrorContains(t, err, "tcp check of "+address+" failed.")
+}
+
+func TestHTTPGet(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(200 * time.Millisecond)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	assert.NoError(t, checks.HTTPGet(nil, server.URL, http.StatusNoContent, time.Second)(context.Background()))
+	assert.ErrorIs(t, checks.HTTPGet(nil, server.URL, http.StatusOK, time.Second)(context.Background()), checks.ErrUnexpectedStatus)
+	assert.ErrorIs(t, checks.HTTPGet(nil, server.URL+"/slow", http.StatusNoContent, 50*time.Millisecond)(context.Background()), context.DeadlineExceeded)
+}
+
+func TestDNS(t *testing.T) {
+	assert.NoError(t, checks.DNS("localhost", time.Second)(context.Background()))
+	assert.ErrorContains(t, checks.DNS("nonexistent.invalid", time.Second)(context.Background()), "dns check of nonexistent.invalid failed.")
+}
+
+func TestNATSConnection(t *testing.T) {
+	assert.ErrorIs(t, checks.NATSConnection(nil)(context.Background()), checks.ErrNotConnected)
+	assert.ErrorIs(t, checks.NATSConnection(&nats.Conn{})(context.Background()), checks.ErrNotConnected)
+}
+
+func TestFileExists(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, checks.FileExists(dir)(context.Background()))
+	assert.ErrorContains(t, checks.FileExists(filepath.Join(dir, "missing"))(context.Background()), "file check of")
+}
+
+func TestDiskFree(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, checks.DiskFree(dir, 1)(context.Background()))
+	assert.ErrorIs(t, checks.DiskFree(dir, 1<<62)(context.Background()), checks.ErrLowDiskSpace)
+}
+
+func TestSQL(t *testing.T) {
+	db := sql.OpenDB(fakeConnector{})
+	defer func() { require.NoError(t, db.Close()) }()
+
+	assert.ErrorIs(t, checks.SQL(db, "fake", time.Second)(context.Background()), errPingFailed)
+}
+
+var errPingFailed = errors.New("ping failed")
+
+// fakeConnector provides connections that fail to ping, without registering a driver
+type fakeConnector struct{}
+
+func (fakeConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	return fakeConn{}, nil
+}
+
+func (c fakeConnector) Driver() driver.Driver {
+	return fakeDriver{}
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) {
+	return fakeConn{}, nil
+}
+
+type fakeConn struct {
+	driver.Conn
+}
+
+func (fakeConn) Ping(ctx context.Context) error {
+	return errPingFailed
+}
+
+func (fakeConn) Close() error {
+	return nil
+}
@@ -0,0 +1,42 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/tombenke/go-12f-common/v2/healthcheck"
+)
+
+var (
+	// ErrLowDiskSpace is returned by the disk free space check if the available space is below the threshold
+	ErrLowDiskSpace = errors.New("low disk space")
+	// ErrUnsupported is returned by the checks that are not supported on the current platform
+	ErrUnsupported = errors.New("unsupported on this platform")
+)
+
+// FileExists returns a check that fails if the file or directory does not exist
+func FileExists(path string) healthcheck.Check {
+	return func(ctx context.Context) error {
+		if _, err := os.Stat(path); err != nil {
+			return checkError("file", path, err)
+		}
+		return nil
+	}
+}
+
+// DiskFree returns a check that fails if the space available to unprivileged users
+// on the file system of the path is less than minFreeBytes
+func DiskFree(path string, minFreeBytes uint64) healthcheck.Check {
+	return func(ctx context.Context) error {
+		free, err := diskFree(path)
+		if err != nil {
+			return checkError("disk", path, err)
+		}
+		if free < minFreeBytes {
+			return checkError("disk", path, fmt.Errorf("%w: %d bytes free, expected at least: %d", ErrLowDiskSpace, free, minFreeBytes))
+		}
+		return nil
+	}
+}
@@ -0,0 +1,52 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/tombenke/go-12f-common/v2/healthcheck"
+)
+
+// ErrNotConnected is returned by the NATS connection check if the connection is not in CONNECTED status
+var ErrNotConnected = errors.New("not connected")
+
+// NATSConnection returns a check that fails if the status of the NATS connection is not CONNECTED
+func NATSConnection(nc *nats.Conn) healthcheck.Check {
+	return func(ctx context.Context) error {
+		if nc == nil {
+			return checkError("nats", "connection", ErrNotConnected)
+		}
+		if status := nc.Status(); status != nats.CONNECTED {
+			return checkError("nats", nc.ConnectedUrlRedacted(), fmt.Errorf("%w: %s", ErrNotConnected, status))
+		}
+		return nil
+	}
+}
+
+// JetStreamStream returns a check that fails if the JetStream stream does not exist
+func JetStreamStream(js jetstream.StreamManager, stream string, timeout time.Duration) healthcheck.Check {
+	return func(ctx context.Context) error {
+		ctx, cancel := withTimeout(ctx, timeout)
+		defer cancel()
+		if _, err := js.Stream(ctx, stream); err != nil {
+			return checkError("jetstream stream", stream, err)
+		}
+		return nil
+	}
+}
+
+// JetStreamConsumer returns a check that fails if the consumer of the JetStream stream does not exist
+func JetStreamConsumer(js jetstream.StreamConsumerManager, stream string, consumer string, timeout time.Duration) healthcheck.Check {
+	return func(ctx context.Context) error {
+		ctx, cancel := withTimeout(ctx, timeout)
+		defer cancel()
+		if _, err := js.Consumer(ctx, stream, consumer); err != nil {
+			return checkError("jetstream consumer", stream+"/"+consumer, err)
+		}
+		return nil
+	}
+}
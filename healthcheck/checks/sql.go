@@ -0,0 +1,21 @@
+package checks
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/tombenke/go-12f-common/v2/healthcheck"
+)
+
+// SQL returns a check that pings the database
+func SQL(db *sql.DB, name string, timeout time.Duration) healthcheck.Check {
+	return func(ctx context.Context) error {
+		ctx, cancel := withTimeout(ctx, timeout)
+		defer cancel()
+		if err := db.PingContext(ctx); err != nil {
+			return checkError("sql", name, err)
+		}
+		return nil
+	}
+}
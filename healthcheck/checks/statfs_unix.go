@@ -0,0 +1,14 @@
+//go:build linux || darwin || freebsd
+
+package checks
+
+import "golang.org/x/sys/unix"
+
+// diskFree returns with the number of bytes available to unprivileged users on the file system of the path
+func diskFree(path string) (uint64, error) {
+	var stat unix.Statfs_t
+	if err := unix.Statfs(path, &stat); err != nil {
+		return 0, err
+	}
+	return uint64(stat.Bavail) * uint64(stat.Bsize), nil //nolint:gosec // the values are never negative
+}
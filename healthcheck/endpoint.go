@@ -0,0 +1,177 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/tombenke/go-12f-common/v2/oti"
+)
+
+// ErrTooManyProbes is returned when a probe arrives while the maximum number of probes are already running on the endpoint
+var ErrTooManyProbes = errors.New("too many concurrent health check probes")
+
+// response is the evaluated result of a health check endpoint
+type response struct {
+	status int
+	body   any
+}
+
+// endpoint evaluates a check or a report, and optionally caches its latest response
+type endpoint struct {
+	path     string
+	evaluate func(ctx context.Context) response
+	config   *Config
+	cached   atomic.Pointer[response]
+	inFlight atomic.Int32
+	// mu guards generation, which is increased by invalidate to drop the refreshes started before it
+	mu         sync.Mutex
+	generation uint64
+}
+
+// newCheckEndpoint creates an endpoint that responds with 503 if the check returns with error
+func newCheckEndpoint(path string, check Check, config *Config, started time.Time) *endpoint {
+	return &endpoint{
+		path:   path,
+		config: config,
+		evaluate: func(ctx context.Context) response {
+			checkResults := make(map[string]string)
+			if err := runCheck(ctx, check); err != nil {
+				checkResults["error"] = err.Error()
+				return response{status: http.StatusServiceUnavailable, body: checkResults}
+			}
+			checkResults["uptime"] = fmt.Sprintf("%v", time.Since(started).Seconds())
+			return response{status: http.StatusOK, body: checkResults}
+		},
+	}
+}
+
+// newReportEndpoint creates an endpoint that responds with 503 only if the report fails
+func newReportEndpoint(path string, report ReportFunc, config *Config, started time.Time) *endpoint {
+	return &endpoint{
+		path:   path,
+		config: config,
+		evaluate: func(ctx context.Context) response {
+			result := report(ctx)
+			status := http.StatusOK
+			if result.Status == StatusFail {
+				status = http.StatusServiceUnavailable
+			}
+			return response{
+				status: status,
+				body: struct {
+					Report
+					Uptime string `json:"uptime"`
+				}{
+					Report: result,
+					Uptime: fmt.Sprintf("%v", time.Since(started).Seconds()),
+				},
+			}
+		},
+	}
+}
+
+// evaluateWithTimeout evaluates the endpoint with the check timeout derived from ctx
+func (e *endpoint) evaluateWithTimeout(ctx context.Context) response {
+	if e.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.config.Timeout)
+		defer cancel()
+	}
+	return e.evaluate(ctx)
+}
+
+// handler returns the HTTP handler of the endpoint.
+// It serves the cached response if there is any, otherwise evaluates the checks within the request context.
+func (e *endpoint) handler(rootCtx context.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if cached := e.cached.Load(); cached != nil {
+			writeResponse(w, cached.status, cached.body)
+			return
+		}
+
+		if inFlight := e.inFlight.Add(1); e.config.MaxConcurrentProbes > 0 && int(inFlight) > e.config.MaxConcurrentProbes {
+			e.inFlight.Add(-1)
+			oti.Log(rootCtx, 0, "Rejected health check probe", "path", e.path, "inFlight", inFlight-1)
+			w.Header().Set("Retry-After", strconv.Itoa(int(max(e.config.Timeout, time.Second).Seconds())))
+			writeResponse(w, http.StatusTooManyRequests, map[string]string{"error": ErrTooManyProbes.Error()})
+			return
+		}
+		defer e.inFlight.Add(-1)
+
+		resp := e.evaluateWithTimeout(oti.CopyLogger(r.Context(), rootCtx))
+		writeResponse(w, resp.status, resp.body)
+	}
+}
+
+// refresh evaluates the endpoint in every interval, and caches the response until done is closed
+func (e *endpoint) refresh(ctx context.Context, interval time.Duration, done <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		e.mu.Lock()
+		generation := e.generation
+		e.mu.Unlock()
+		resp := e.evaluateWithTimeout(ctx)
+		e.store(resp, generation)
+		select {
+		case <-ticker.C:
+		case <-done:
+			return
+		}
+	}
+}
+
+// store caches the response, unless the endpoint has been invalidated since the evaluation started
+func (e *endpoint) store(resp response, generation uint64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.generation == generation {
+		e.cached.Store(&resp)
+	}
+}
+
+// invalidate drops the cached response, so the checks are evaluated by the probes until the next refresh
+func (e *endpoint) invalidate() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.generation++
+	e.cached.Store(nil)
+}
+
+// runCheck calls the check, but returns with the error of the context if it is done before the check returns
+func runCheck(ctx context.Context, check Check) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- check(ctx)
+	}()
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("health check timed out. %w", ctx.Err())
+	}
+}
+
+// runChecks calls the checks of the components concurrently, and returns with their errors in the same order
+func runChecks(ctx context.Context, components []Component) []error {
+	errs := make([]error, len(components))
+	var wg sync.WaitGroup
+	for i, c := range components {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = runCheck(ctx, c.Check)
+		}()
+	}
+	wg.Wait()
+	return errs
+}
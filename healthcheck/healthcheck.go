@@ -6,14 +6,14 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
 	"sync"
 	"time"
 
+	"github.com/tombenke/go-12f-common/v2/admin"
 	"github.com/tombenke/go-12f-common/v2/log"
-	"github.com/tombenke/go-12f-common/v2/must"
 	"github.com/tombenke/go-12f-common/v2/oti"
-	"golang.org/x/exp/maps"
 )
 
 type ServiceNotAvailableError struct{}
@@ -23,113 +23,159 @@ func (e ServiceNotAvailableError) Error() string {
 }
 
 type HealthCheck struct {
-	config Config
-	server *http.Server
-	wg     *sync.WaitGroup
+	config    Config
+	server    *http.Server
+	listener  net.Listener
+	wg        *sync.WaitGroup
+	refreshWg sync.WaitGroup
+	done      chan struct{}
+	endpoints []*endpoint
 }
 
 // Check is a health/readiness checker function
 type Check func(ctx context.Context) error
 
 type Config struct {
-	Port   uint
+	// Address is the bind address of the server. Binds to all interfaces if empty.
+	Address string
+	// Port to listen on. A random free port is used if zero, see HealthCheck.Addr().
+	Port uint
+	// Checks are simple check endpoints, that respond with 503 if the check returns with error
 	Checks map[string]Check
+	// Reports are check endpoints that report the status of each component.
+	// They respond with 503 only if the report fails, otherwise with 200 and the status of the components.
+	Reports map[string]ReportFunc
+	// Timeout limits the duration of the checks of an endpoint evaluation. No limit if zero.
+	Timeout time.Duration
+	// Interval of evaluating the checks in the background. If it is greater than zero,
+	// the endpoints respond with the cached result of the latest evaluation instead of calling the checks.
+	Interval time.Duration
+	// MaxConcurrentProbes limits the number of probes that evaluate the checks of an endpoint at the same time.
+	// The probes over the limit are rejected with 429. No limit if zero.
+	MaxConcurrentProbes int
+	// Server hosts the check endpoints as public routes if it is set, instead of an own server listening on Port
+	Server *admin.Server
 }
 
 // Create a HealthCheck instance
-func NewHealthCheck(wg *sync.WaitGroup, config Config) HealthCheck {
-	return HealthCheck{wg: wg, config: config}
+func NewHealthCheck(config Config) HealthCheck {
+	return HealthCheck{config: config}
 }
 
-// Setup the Healtcheck services and start listening on the HealtCheck port
-func (h *HealthCheck) Startup(ctx context.Context) {
+// Setup the Healtcheck services and start listening on the HealtCheck address and port.
+// The server goroutine is tracked by wg. It returns with error if the server can not listen, e.g. the port is already taken.
+func (h *HealthCheck) Startup(ctx context.Context, wg *sync.WaitGroup) error {
 	_, logger := h.getLogger(ctx)
+	h.wg = wg
 	logger.Info("Starting up")
 	started := time.Now()
 	mux := http.NewServeMux()
 
+	// Open the listener first, so the endpoints are reachable as soon as Startup returns
+	if h.config.Server == nil {
+		listener, err := net.Listen("tcp", net.JoinHostPort(h.config.Address, fmt.Sprintf("%d", h.config.Port)))
+		if err != nil {
+			return fmt.Errorf("failed to listen for healthcheck server. %w", err)
+		}
+		h.listener = listener
+	}
+
+	endpoints := make([]*endpoint, 0, len(h.config.Checks)+len(h.config.Reports))
 	for path, check := range h.config.Checks {
-		logger.Debug("Adding endpoint", "path", path)
-		endpointPath := path
-		checkFun := check
-		mux.HandleFunc(endpointPath, func(w http.ResponseWriter, r *http.Request) {
-			checkResults := make(map[string]string)
-			err := checkFun(ctx)
-			status := http.StatusOK
-			if err != nil {
-				// write out the response code and content type header
-				status = http.StatusServiceUnavailable
-				checkResults["error"] = err.Error()
-			} else {
-				duration := time.Since(started)
-				checkResults["uptime"] = fmt.Sprintf("%v", duration.Seconds())
-			}
-			w.Header().Set("Content-Type", "application/json; charset=utf-8")
-			w.WriteHeader(status)
-
-			encoder := json.NewEncoder(w)
-			encoder.SetIndent("", "    ")
-			must.Must(encoder.Encode(checkResults))
-		})
+		endpoints = append(endpoints, newCheckEndpoint(path, check, &h.config, started))
+	}
+	for path, report := range h.config.Reports {
+		endpoints = append(endpoints, newReportEndpoint(path, report, &h.config, started))
+	}
+
+	h.endpoints = endpoints
+	h.done = make(chan struct{})
+	for _, e := range endpoints {
+		logger.Debug("Adding endpoint", "path", e.path)
+		if h.config.Server != nil {
+			h.config.Server.HandleFunc(e.path, e.handler(ctx), admin.Public())
+		} else {
+			mux.HandleFunc(e.path, e.handler(ctx))
+		}
+
+		if h.config.Interval > 0 {
+			h.refreshWg.Add(1)
+			go func() {
+				defer h.refreshWg.Done()
+				e.refresh(ctx, h.config.Interval, h.done)
+			}()
+		}
 	}
 
-	_, cancelCtx := context.WithCancel(context.Background())
-	h.server = &http.Server{
-		Addr:    fmt.Sprintf(":%d", h.config.Port),
-		Handler: mux,
+	if h.config.Server != nil {
+		logger.Info("HealthCheck endpoints are hosted by the admin server")
+		return nil
 	}
 
+	h.server = &http.Server{Handler: mux}
+
 	// Start the blocking server call in a separate thread
 	h.wg.Add(1)
 	go func() {
-		err := h.server.ListenAndServe()
+		defer h.wg.Done()
+		err := h.server.Serve(h.listener)
 		if errors.Is(err, http.ErrServerClosed) {
 			logger.Info("Server closed")
 		} else if err != nil {
-			logger.Error("Error listening for server", "error", err)
+			logger.Error("Error serving healthcheck server", log.ErrorAttr(err))
 		}
-		cancelCtx()
 	}()
 
-	h.waitUntilServerStarted(ctx)
+	logger.Info("HealthCheck is up and running!", "address", h.Addr())
+	return nil
 }
 
-func (h *HealthCheck) waitUntilServerStarted(ctx context.Context) {
-	_, logger := h.getLogger(ctx)
-	if len(h.config.Checks) > 0 {
-		for {
-			time.Sleep(10 * time.Millisecond)
-
-			logger.Info("Checking if server started...")
-			resp, err := http.Get(fmt.Sprintf("http://localhost:%d%s", h.config.Port, maps.Keys(h.config.Checks)[0]))
-			if err != nil {
-				logger.Error("Server check failed", "error", err)
-				continue
-			}
-			logger.Debug("Checking response", "statusCode", resp.StatusCode)
-			if err := resp.Body.Close(); err != nil {
-				logger.Error("Failed to close response body", "err", err)
-				return
-			}
-
-			if resp.StatusCode != http.StatusOK {
-				logger.Error("Server response is not OK", "statusCode", resp.StatusCode)
-				continue
-			}
-
-			// If reached this point then server is up and running!
-			logger.Info("Server is up and running")
-			break
-		}
+// Addr returns with the address the HealthCheck server listens on,
+// or nil if it has not been started or the endpoints are hosted by the admin server.
+func (h *HealthCheck) Addr() net.Addr {
+	if h.listener == nil {
+		return nil
 	}
-	logger.Info("HealthCheck is up and running!")
+	return h.listener.Addr()
 }
 
-// Shut down the HealtCheck services
-func (h *HealthCheck) Shutdown(ctx context.Context) {
-	defer h.wg.Done()
-	slog.InfoContext(ctx, "Shutdown", string(oti.FieldComponent), "HealthCheck")
-	must.Must(h.server.Shutdown(context.Background()))
+// Invalidate drops the cached responses of the endpoints, e.g. when the state of the application changes,
+// so the probes evaluate the checks until the next refresh instead of getting a stale response.
+func (h *HealthCheck) Invalidate() {
+	for _, e := range h.endpoints {
+		e.invalidate()
+	}
+}
+
+// writeResponse writes the response body as indented JSON with the given status code
+func writeResponse(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "    ")
+	if err := encoder.Encode(body); err != nil {
+		log.ErrorContext(context.Background(), "Failed to write response", log.ErrorAttr(err), string(oti.FieldComponent), "HealthCheck")
+	}
+}
+
+// Shut down the HealtCheck services within the deadline of ctx
+func (h *HealthCheck) Shutdown(ctx context.Context) error {
+	_, logger := h.getLogger(ctx)
+	logger.Info("Shutdown")
+	if h.done == nil {
+		return nil
+	}
+	close(h.done)
+	h.done = nil
+	h.refreshWg.Wait()
+	if h.server == nil {
+		return nil
+	}
+	if err := h.server.Shutdown(ctx); err != nil {
+		return fmt.Errorf("failed to shut down healthcheck server. %w", err)
+	}
+	return nil
 }
 
 func (h *HealthCheck) getLogger(ctx context.Context) (context.Context, *slog.Logger) {
@@ -2,39 +2,298 @@ package healthcheck_test
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/tombenke/go-12f-common/v2/healthcheck"
 	"github.com/tombenke/go-12f-common/v2/log"
 )
 
 func TestHealthCheckServer(t *testing.T) {
-	log.SetupDefault("debug", "text")
+	require.NoError(t, log.SetupDefault("debug", "text"))
 	wg := sync.WaitGroup{}
 	hc := healthcheck.NewHealthCheck(
-		&wg,
 		healthcheck.Config{
-			8082,
-			map[string]healthcheck.Check{
+			Address: "127.0.0.1",
+			Checks: map[string]healthcheck.Check{
 				"/live":  func(ctx context.Context) error { return nil },
 				"/ready": func(ctx context.Context) error { return nil },
 			},
 		},
 	)
-	hc.Startup(context.Background())
-	checkEndpoints(t)
-	hc.Shutdown(context.Background())
+	require.NoError(t, hc.Startup(context.Background(), &wg))
+	checkEndpoints(t, "http://"+hc.Addr().String())
+	require.NoError(t, hc.Shutdown(context.Background()))
 	wg.Wait()
 }
 
-func checkEndpoints(t *testing.T) {
-	checkEndpoint(t, "http://localhost:8082/live")
-	checkEndpoint(t, "http://localhost:8082/ready")
+func TestHealthCheckReports(t *testing.T) {
+	t.Parallel()
+	failing := func(ctx context.Context) error { return errors.New("connection refused") }
+	passing := func(ctx context.Context) error { return nil }
+	wg := sync.WaitGroup{}
+	hc := healthcheck.NewHealthCheck(
+		healthcheck.Config{
+			Address: "127.0.0.1",
+			Checks: map[string]healthcheck.Check{
+				"/live": passing,
+			},
+			Reports: map[string]healthcheck.ReportFunc{
+				"/degraded": func(ctx context.Context) healthcheck.Report {
+					return healthcheck.Evaluate(ctx, []healthcheck.Component{
+						{Name: "Worker", Critical: true, Check: passing},
+						{Name: "Cache", Critical: false, Check: failing},
+					})
+				},
+				"/failed": func(ctx context.Context) healthcheck.Report {
+					return healthcheck.Evaluate(ctx, []healthcheck.Component{
+						{Name: "Worker", Critical: true, Check: failing},
+						{Name: "Cache", Critical: false, Check: passing},
+					})
+				},
+			},
+		},
+	)
+	require.NoError(t, hc.Startup(context.Background(), &wg))
+	baseURL := "http://" + hc.Addr().String()
+
+	report := checkReport(t, baseURL+"/degraded", http.StatusOK)
+	assert.Equal(t, healthcheck.StatusWarn, report.Status)
+	assert.Equal(t, healthcheck.StatusPass, report.Components["Worker"].Status)
+	assert.Equal(t, healthcheck.StatusWarn, report.Components["Cache"].Status)
+	assert.Equal(t, "connection refused", report.Components["Cache"].Error)
+
+	report = checkReport(t, baseURL+"/failed", http.StatusServiceUnavailable)
+	assert.Equal(t, healthcheck.StatusFail, report.Status)
+	assert.Equal(t, healthcheck.StatusFail, report.Components["Worker"].Status)
+	assert.Equal(t, healthcheck.StatusPass, report.Components["Cache"].Status)
+	assert.EqualError(t, report.Err(), "Worker: connection refused")
+
+	require.NoError(t, hc.Shutdown(context.Background()))
+	wg.Wait()
+}
+
+func TestReportErrOrder(t *testing.T) {
+	report := healthcheck.Report{
+		Status: healthcheck.StatusFail,
+		Output: "not ready",
+		Components: map[string]healthcheck.ComponentResult{
+			"Worker": {Status: healthcheck.StatusFail, Critical: true, Error: "stalled"},
+			"Cache":  {Status: healthcheck.StatusFail, Critical: false, Error: "timeout"},
+			"Broker": {Status: healthcheck.StatusFail, Critical: true, Error: "disconnected"},
+			"DB":     {Status: healthcheck.StatusFail, Critical: true, Error: "connection refused"},
+		},
+	}
+	for range 10 {
+		assert.EqualError(t, report.Err(), "Broker: disconnected\nDB: connection refused\nWorker: stalled\nnot ready")
+	}
+}
+
+func TestHealthCheckTimeout(t *testing.T) {
+	t.Parallel()
+	blocking := func(ctx context.Context) error { time.Sleep(time.Second); return nil }
+	wg := sync.WaitGroup{}
+	hc := healthcheck.NewHealthCheck(
+		healthcheck.Config{
+			Address: "127.0.0.1",
+			Checks: map[string]healthcheck.Check{
+				"/live": func(ctx context.Context) error { return nil },
+			},
+			Reports: map[string]healthcheck.ReportFunc{
+				"/ready": func(ctx context.Context) healthcheck.Report {
+					return healthcheck.Evaluate(ctx, []healthcheck.Component{
+						{Name: "Slow", Critical: true, Check: blocking},
+						{Name: "OtherSlow", Critical: true, Check: blocking},
+					})
+				},
+			},
+			Timeout: 100 * time.Millisecond,
+		},
+	)
+	require.NoError(t, hc.Startup(context.Background(), &wg))
+	baseURL := "http://" + hc.Addr().String()
+
+	began := time.Now()
+	report := checkReport(t, baseURL+"/ready", http.StatusServiceUnavailable)
+	assert.Less(t, time.Since(began), 500*time.Millisecond)
+	assert.Contains(t, report.Components["Slow"].Error, context.DeadlineExceeded.Error())
+	assert.Contains(t, report.Components["OtherSlow"].Error, context.DeadlineExceeded.Error())
+
+	require.NoError(t, hc.Shutdown(context.Background()))
+	wg.Wait()
+}
+
+func TestHealthCheckCachedResults(t *testing.T) {
+	t.Parallel()
+	var calls atomic.Int32
+	wg := sync.WaitGroup{}
+	hc := healthcheck.NewHealthCheck(
+		healthcheck.Config{
+			Address: "127.0.0.1",
+			Checks: map[string]healthcheck.Check{
+				"/live": func(ctx context.Context) error { calls.Add(1); return nil },
+			},
+			Interval: time.Hour,
+		},
+	)
+	require.NoError(t, hc.Startup(context.Background(), &wg))
+	baseURL := "http://" + hc.Addr().String()
+	for range 5 {
+		checkEndpoint(t, baseURL+"/live")
+	}
+	assert.Equal(t, int32(1), calls.Load())
+	require.NoError(t, hc.Shutdown(context.Background()))
+	wg.Wait()
+}
+
+func TestHealthCheckInvalidate(t *testing.T) {
+	t.Parallel()
+	var failing atomic.Bool
+	wg := sync.WaitGroup{}
+	hc := healthcheck.NewHealthCheck(
+		healthcheck.Config{
+			Address: "127.0.0.1",
+			Checks: map[string]healthcheck.Check{
+				"/ready": func(ctx context.Context) error {
+					if failing.Load() {
+						return errors.New("not ready")
+					}
+					return nil
+				},
+			},
+			Interval: time.Hour,
+		},
+	)
+	require.NoError(t, hc.Startup(context.Background(), &wg))
+	url := "http://" + hc.Addr().String() + "/ready"
+	require.Eventually(t, func() bool { return getStatus(t, url) == http.StatusOK }, time.Second, 10*time.Millisecond)
+
+	// The cached response is served until it is invalidated
+	failing.Store(true)
+	assert.Equal(t, http.StatusOK, getStatus(t, url))
+	hc.Invalidate()
+	assert.Equal(t, http.StatusServiceUnavailable, getStatus(t, url))
+
+	require.NoError(t, hc.Shutdown(context.Background()))
+	wg.Wait()
+}
+
+func TestHealthCheckRejectsConcurrentProbes(t *testing.T) {
+	t.Parallel()
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	wg := sync.WaitGroup{}
+	hc := healthcheck.NewHealthCheck(
+		healthcheck.Config{
+			Address: "127.0.0.1",
+			Checks: map[string]healthcheck.Check{
+				"/live": func(ctx context.Context) error { return nil },
+			},
+			Reports: map[string]healthcheck.ReportFunc{
+				"/ready": func(ctx context.Context) healthcheck.Report {
+					close(entered)
+					<-release
+					return healthcheck.Report{Status: healthcheck.StatusPass}
+				},
+			},
+			MaxConcurrentProbes: 1,
+		},
+	)
+	require.NoError(t, hc.Startup(context.Background(), &wg))
+	baseURL := "http://" + hc.Addr().String()
+
+	firstDone := make(chan int)
+	go func() {
+		res, err := http.Get(baseURL + "/ready")
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+		firstDone <- res.StatusCode
+	}()
+	<-entered
+	res, err := http.Get(baseURL + "/ready")
+	require.NoError(t, err)
+	require.NoError(t, res.Body.Close())
+	assert.Equal(t, http.StatusTooManyRequests, res.StatusCode)
+
+	close(release)
+	assert.Equal(t, http.StatusOK, <-firstDone)
+
+	require.NoError(t, hc.Shutdown(context.Background()))
+	wg.Wait()
+}
+
+func TestHealthCheckPortTaken(t *testing.T) {
+	t.Parallel()
+	wg := sync.WaitGroup{}
+	config := healthcheck.Config{
+		Address: "127.0.0.1",
+		Checks: map[string]healthcheck.Check{
+			"/live": func(ctx context.Context) error { return nil },
+		},
+	}
+	hc := healthcheck.NewHealthCheck(config)
+	require.NoError(t, hc.Startup(context.Background(), &wg))
+
+	config.Port = uint(hc.Addr().(*net.TCPAddr).Port)
+	other := healthcheck.NewHealthCheck(config)
+	assert.Error(t, other.Startup(context.Background(), &wg))
+	assert.Nil(t, other.Addr())
+
+	require.NoError(t, hc.Shutdown(context.Background()))
+	wg.Wait()
+}
+
+func TestHealthCheckShutdownDeadline(t *testing.T) {
+	t.Parallel()
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	wg := sync.WaitGroup{}
+	hc := healthcheck.NewHealthCheck(healthcheck.Config{
+		Address: "127.0.0.1",
+		Checks: map[string]healthcheck.Check{
+			"/live": func(ctx context.Context) error {
+				close(entered)
+				<-release
+				return nil
+			},
+		},
+	})
+	require.NoError(t, hc.Startup(context.Background(), &wg))
+
+	go func() {
+		if res, err := http.Get("http://" + hc.Addr().String() + "/live"); err == nil {
+			_ = res.Body.Close()
+		}
+	}()
+	<-entered
+
+	// The in-flight probe blocks the graceful shutdown until the deadline
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, hc.Shutdown(ctx), context.DeadlineExceeded)
+	close(release)
+	wg.Wait()
+}
+
+func TestHealthCheckShutdownWithoutStartup(t *testing.T) {
+	t.Parallel()
+	hc := healthcheck.NewHealthCheck(healthcheck.Config{})
+	assert.NoError(t, hc.Shutdown(context.Background()))
+}
+
+func checkEndpoints(t *testing.T, baseURL string) {
+	checkEndpoint(t, baseURL+"/live")
+	checkEndpoint(t, baseURL+"/ready")
 }
 
 func checkEndpoint(t *testing.T, requestURL string) {
@@ -48,3 +307,21 @@ func checkEndpoint(t *testing.T, requestURL string) {
 	fmt.Printf("client: status code: %d\n", res.StatusCode)
 	assert.Equal(t, 200, res.StatusCode)
 }
+
+func checkReport(t *testing.T, requestURL string, expectedStatusCode int) healthcheck.Report {
+	res, err := http.Get(requestURL)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, res.Body.Close()) }()
+	assert.Equal(t, expectedStatusCode, res.StatusCode)
+
+	report := healthcheck.Report{}
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&report))
+	return report
+}
+
+func getStatus(t *testing.T, requestURL string) int {
+	res, err := http.Get(requestURL)
+	require.NoError(t, err)
+	require.NoError(t, res.Body.Close())
+	return res.StatusCode
+}
@@ -0,0 +1,141 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+
+	"github.com/tombenke/go-12f-common/v2/oti"
+	"go.opentelemetry.io/otel/attribute"
+	metric_api "go.opentelemetry.io/otel/metric"
+)
+
+// Status is the health status of a check or of a whole report.
+// The values follow the "Health Check Response Format for HTTP APIs" IETF draft.
+type Status string
+
+const (
+	// StatusPass means healthy
+	StatusPass Status = "pass"
+	// StatusWarn means healthy, with some concerns, e.g. a non-critical dependency failed
+	StatusWarn Status = "warn"
+	// StatusFail means unhealthy
+	StatusFail Status = "fail"
+
+	// HealthStatusMetricName is the name of the gauge that reports the health status of the components
+	HealthStatusMetricName = "health_status"
+
+	// FieldCritical is the metric attribute that tells whether the component is critical
+	FieldCritical = attribute.Key("critical")
+)
+
+// gaugeValue returns the value that represents the status in the health status gauge
+func (s Status) gaugeValue() int64 {
+	switch s {
+	case StatusPass:
+		return 2
+	case StatusWarn:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Component is a named health check of an application component
+type Component struct {
+	// Name identifies the component in the report and in the metrics
+	Name string
+	// Critical components make the whole report fail, non-critical ones only degrade it to warn
+	Critical bool
+	// Check is the health checker function of the component
+	Check Check
+}
+
+// ComponentResult holds the outcome of the health check of a single component
+type ComponentResult struct {
+	Status   Status `json:"status"`
+	Critical bool   `json:"critical"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Report holds the aggregated outcome of the health checks of several components
+type Report struct {
+	Status     Status                     `json:"status"`
+	Output     string                     `json:"output,omitempty"`
+	Components map[string]ComponentResult `json:"components,omitempty"`
+}
+
+// ReportFunc is a health/readiness checker function that reports the status of each component
+type ReportFunc func(ctx context.Context) Report
+
+// Err returns with an error that joins the errors of the failing critical components in the order of their names and the output of the report,
+// or nil if the report does not fail.
+func (r Report) Err() error {
+	if r.Status != StatusFail {
+		return nil
+	}
+	var errs []error
+	for _, name := range slices.Sorted(maps.Keys(r.Components)) {
+		if result := r.Components[name]; result.Critical && result.Status == StatusFail {
+			errs = append(errs, errors.New(name+": "+result.Error))
+		}
+	}
+	if r.Output != "" {
+		errs = append(errs, errors.New(r.Output))
+	}
+	if len(errs) == 0 {
+		return ServiceNotAvailableError{}
+	}
+	return errors.Join(errs...)
+}
+
+// Evaluate calls the checks of the components concurrently and aggregates their results into a report.
+// A check that does not return until ctx is done fails with the error of the context.
+// The report fails if any critical component fails, and warns if only non-critical components fail.
+// The status of each component is also recorded by the health status gauge.
+func Evaluate(ctx context.Context, components []Component) Report {
+	report := Report{
+		Status:     StatusPass,
+		Components: make(map[string]ComponentResult, len(components)),
+	}
+	errs := runChecks(ctx, components)
+	for i, c := range components {
+		result := ComponentResult{Status: StatusPass, Critical: c.Critical}
+		if err := errs[i]; err != nil {
+			result.Error = err.Error()
+			result.Status = StatusWarn
+			if c.Critical {
+				result.Status = StatusFail
+			}
+		}
+		report.Components[c.Name] = result
+		report.Status = worse(report.Status, result.Status)
+		recordStatus(ctx, c.Name, result)
+	}
+	return report
+}
+
+// worse returns with the more severe status of the two
+func worse(a, b Status) Status {
+	if b.gaugeValue() < a.gaugeValue() {
+		return b
+	}
+	return a
+}
+
+// recordStatus reports the status of a component via the health status gauge
+func recordStatus(ctx context.Context, name string, result ComponentResult) {
+	gauge, err := oti.Int64GaugeGetInstrument(
+		HealthStatusMetricName,
+		metric_api.WithDescription("The health status of the component: 2=pass, 1=warn, 0=fail"),
+	)
+	if err != nil {
+		oti.LogError(ctx, err, "unable to instantiate gauge", oti.FieldMetricName, HealthStatusMetricName)
+		return
+	}
+	gauge.Record(ctx, result.Status.gaugeValue(), metric_api.WithAttributes(
+		oti.FieldComponent.String(name),
+		FieldCritical.Bool(result.Critical),
+	))
+}
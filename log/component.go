@@ -0,0 +1,141 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// FieldComponent is the attribute that identifies the component of the application in the log records.
+// It is the same key as oti.FieldComponent.
+const FieldComponent = "component"
+
+// ErrInvalidComponentLevel is returned when parsing a component level that is not in the `component=level` format
+var ErrInvalidComponentLevel = errors.New("invalid component log level")
+
+var (
+	// componentLevels holds the levels of the components that override the level of the default logger
+	componentLevels atomic.Pointer[map[string]slog.Level]
+	// componentLevelsMu serializes the changes of componentLevels
+	componentLevelsMu sync.Mutex
+)
+
+// ParseComponentLevels parses the `component=level` pairs, e.g. `Worker=debug`, into a map of the component levels
+func ParseComponentLevels(pairs []string) (map[string]slog.Level, error) {
+	levels := make(map[string]slog.Level, len(pairs))
+	for _, pair := range pairs {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		component, name, ok := strings.Cut(pair, "=")
+		component = strings.TrimSpace(component)
+		if !ok || component == "" {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidComponentLevel, pair)
+		}
+		l, err := ParseLevel(strings.TrimSpace(name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the log level of %s. %w", component, err)
+		}
+		levels[component] = l
+	}
+	return levels, nil
+}
+
+// SetComponentLevels replaces the levels of all components. The components without level log at the level of the default logger.
+func SetComponentLevels(levels map[string]slog.Level) {
+	componentLevelsMu.Lock()
+	defer componentLevelsMu.Unlock()
+	levels = maps.Clone(levels)
+	if levels == nil {
+		levels = map[string]slog.Level{}
+	}
+	componentLevels.Store(&levels)
+}
+
+// SetComponentLevel sets the level of the loggers that have the component attribute with the given value
+func SetComponentLevel(component string, l slog.Level) {
+	componentLevelsMu.Lock()
+	defer componentLevelsMu.Unlock()
+	levels := ComponentLevels()
+	levels[component] = l
+	componentLevels.Store(&levels)
+}
+
+// ResetComponentLevel makes the component log at the level of the default logger again
+func ResetComponentLevel(component string) {
+	componentLevelsMu.Lock()
+	defer componentLevelsMu.Unlock()
+	levels := ComponentLevels()
+	delete(levels, component)
+	componentLevels.Store(&levels)
+}
+
+// ComponentLevels returns with a copy of the levels of the components
+func ComponentLevels() map[string]slog.Level {
+	if levels := componentLevels.Load(); levels != nil {
+		return maps.Clone(*levels)
+	}
+	return map[string]slog.Level{}
+}
+
+// componentLevel returns with the level of the component, or false if it has no own level
+func componentLevel(component string) (slog.Level, bool) {
+	levels := componentLevels.Load()
+	if levels == nil {
+		return 0, false
+	}
+	l, ok := (*levels)[component]
+	return l, ok
+}
+
+// componentLevelHandler is a slog.Handler wrapper, that applies the level of the component
+// set by the component attribute of the logger, instead of the level of the wrapped handler.
+type componentLevelHandler struct {
+	next      slog.Handler
+	component string
+	grouped   bool
+}
+
+// NewComponentLevelHandler wraps the handler to apply the levels of the components set by SetComponentLevels
+func NewComponentLevelHandler(next slog.Handler) slog.Handler {
+	return &componentLevelHandler{next: next}
+}
+
+func (h *componentLevelHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	if h.component != "" {
+		if componentLevel, ok := componentLevel(h.component); ok {
+			return l >= componentLevel
+		}
+	}
+	return h.next.Enabled(ctx, l)
+}
+
+func (h *componentLevelHandler) Handle(ctx context.Context, record slog.Record) error {
+	return h.next.Handle(ctx, record)
+}
+
+func (h *componentLevelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	child := *h
+	child.next = h.next.WithAttrs(attrs)
+	if !h.grouped {
+		// The last component attribute wins, e.g. a sub-component overrides its parent
+		for _, attr := range attrs {
+			if attr.Key == FieldComponent {
+				child.component = attr.Value.String()
+			}
+		}
+	}
+	return &child
+}
+
+func (h *componentLevelHandler) WithGroup(name string) slog.Handler {
+	child := *h
+	child.next = h.next.WithGroup(name)
+	child.grouped = child.grouped || name != ""
+	return &child
+}
@@ -0,0 +1,56 @@
+package log_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tombenke/go-12f-common/v2/log"
+)
+
+func TestParseComponentLevels(t *testing.T) {
+	levels, err := log.ParseComponentLevels([]string{"Worker=debug", " HealthCheck = warn ", ""})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]slog.Level{"Worker": slog.LevelDebug, "HealthCheck": slog.LevelWarn}, levels)
+
+	_, err = log.ParseComponentLevels([]string{"Worker"})
+	assert.ErrorIs(t, err, log.ErrInvalidComponentLevel)
+	_, err = log.ParseComponentLevels([]string{"Worker=verbose"})
+	assert.ErrorIs(t, err, log.ErrUnknownLevel)
+}
+
+func TestComponentLevelHandler(t *testing.T) {
+	buf := bytes.Buffer{}
+	logger := slog.New(log.NewComponentLevelHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo})))
+	log.SetComponentLevels(map[string]slog.Level{"Worker": slog.LevelDebug, "HealthCheck": slog.LevelWarn})
+	defer log.SetComponentLevels(nil)
+	ctx := context.Background()
+
+	worker := logger.With(log.FieldComponent, "Worker")
+	healthCheck := logger.With(log.FieldComponent, "HealthCheck")
+	assert.True(t, worker.Enabled(ctx, slog.LevelDebug))
+	assert.False(t, healthCheck.Enabled(ctx, slog.LevelInfo))
+	assert.False(t, logger.Enabled(ctx, slog.LevelDebug))
+	assert.True(t, logger.Enabled(ctx, slog.LevelInfo))
+
+	// The last component attribute wins, and the attributes in groups are ignored
+	assert.False(t, worker.With(log.FieldComponent, "HealthCheck").Enabled(ctx, slog.LevelInfo))
+	assert.True(t, worker.WithGroup("request").With(log.FieldComponent, "HealthCheck").Enabled(ctx, slog.LevelDebug))
+
+	worker.Debug("debug message of the worker")
+	assert.Contains(t, buf.String(), "debug message of the worker")
+
+	// The levels can be changed at runtime
+	log.SetComponentLevel("HealthCheck", slog.LevelDebug)
+	assert.True(t, healthCheck.Enabled(ctx, slog.LevelDebug))
+	log.ResetComponentLevel("Worker")
+	assert.False(t, worker.Enabled(ctx, slog.LevelDebug))
+	assert.Equal(t, map[string]slog.Level{"HealthCheck": slog.LevelDebug}, log.ComponentLevels())
+
+	log.SetComponentLevels(nil)
+	log.SetComponentLevel("Worker", slog.LevelDebug)
+	assert.True(t, worker.Enabled(ctx, slog.LevelDebug))
+}
@@ -0,0 +1,254 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+const (
+	// consoleTimeFormat is the fixed width time format of the console records
+	consoleTimeFormat = "15:04:05.000"
+	// consoleLevelWidth is the width of the level names, so the messages are aligned
+	consoleLevelWidth = 5
+	// consoleIndent indents the lines of the multi-line errors
+	consoleIndent = "    "
+
+	colorReset   = "\033[0m"
+	colorBold    = "\033[1m"
+	colorFaint   = "\033[2m"
+	colorRed     = "\033[31m"
+	colorGreen   = "\033[32m"
+	colorYellow  = "\033[33m"
+	colorBlue    = "\033[34m"
+	colorMagenta = "\033[35m"
+	colorCyan    = "\033[36m"
+)
+
+// ConsoleHandlerOptions are the options of the console handler
+type ConsoleHandlerOptions struct {
+	// Level is the minimum level of the records. Info if nil.
+	Level slog.Leveler
+	// Color enables the ANSI colors
+	Color bool
+}
+
+// consoleHandler is a slog.Handler, that writes human-friendly records for development, e.g.
+//
+//	12:04:05.123 INFO  [Worker] Processing message id=42
+type consoleHandler struct {
+	w    io.Writer
+	mu   *sync.Mutex
+	opts ConsoleHandlerOptions
+
+	// component is the value of the component attribute of the logger, that prefixes the messages
+	component string
+	// attrs are the formatted attributes of the logger
+	attrs []byte
+	// groups are the open groups of the logger
+	groups []string
+}
+
+// NewConsoleHandler creates a handler that writes the records in the console format to w.
+// The records start with the time, the level and the component, then the message and the attributes follow.
+// The multi-line errors and strings, e.g. the stack traces of the panics captured by oti.TryCatch, are written below the record indented.
+func NewConsoleHandler(w io.Writer, opts *ConsoleHandlerOptions) slog.Handler {
+	h := &consoleHandler{w: w, mu: &sync.Mutex{}}
+	if opts != nil {
+		h.opts = *opts
+	}
+	if h.opts.Level == nil {
+		h.opts.Level = slog.LevelInfo
+	}
+	return h
+}
+
+func (h *consoleHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	return l >= h.opts.Level.Level()
+}
+
+func (h *consoleHandler) Handle(ctx context.Context, record slog.Record) error {
+	buf := bytes.Buffer{}
+	component := h.component
+	attrs := slices.Clone(h.attrs)
+	var details []string
+	record.Attrs(func(a slog.Attr) bool {
+		if len(h.groups) == 0 && a.Key == FieldComponent {
+			component = a.Value.Resolve().String()
+			return true
+		}
+		attrs = h.appendAttr(attrs, &details, h.groups, a)
+		return true
+	})
+
+	if !record.Time.IsZero() {
+		h.colorize(&buf, colorFaint, record.Time.Format(consoleTimeFormat))
+		buf.WriteByte(' ')
+	}
+	h.colorize(&buf, levelColor(record.Level), fmt.Sprintf("%-*s", consoleLevelWidth, LevelName(record.Level)))
+	buf.WriteByte(' ')
+	if component != "" {
+		h.colorize(&buf, colorCyan, "["+component+"]")
+		buf.WriteByte(' ')
+	}
+	h.colorize(&buf, colorBold, record.Message)
+	buf.Write(attrs)
+	buf.WriteByte('\n')
+	for _, detail := range details {
+		for _, line := range strings.Split(strings.TrimRight(detail, "\n"), "\n") {
+			h.colorize(&buf, colorRed, consoleIndent+line)
+			buf.WriteByte('\n')
+		}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := h.w.Write(buf.Bytes())
+	return err //nolint:wrapcheck // io.Writer errors are returned as is
+}
+
+func (h *consoleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	child := *h
+	child.attrs = slices.Clone(h.attrs)
+	for _, a := range attrs {
+		if len(h.groups) == 0 && a.Key == FieldComponent {
+			// The last component attribute wins, e.g. a sub-component overrides its parent
+			child.component = a.Value.Resolve().String()
+			continue
+		}
+		// The multi-line errors of the logger are written inline
+		child.attrs = h.appendAttr(child.attrs, nil, h.groups, a)
+	}
+	return &child
+}
+
+func (h *consoleHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	child := *h
+	child.groups = append(slices.Clone(h.groups), name)
+	return &child
+}
+
+// appendAttr appends the attribute as ` key=value` to buf, and the multi-line errors to details, if it is not nil
+func (h *consoleHandler) appendAttr(buf []byte, details *[]string, groups []string, a slog.Attr) []byte {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return buf
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			groups = append(slices.Clone(groups), a.Key)
+		}
+		for _, attr := range a.Value.Group() {
+			buf = h.appendAttr(buf, details, groups, attr)
+		}
+		return buf
+	}
+
+	key := strings.Join(append(slices.Clone(groups), a.Key), ".")
+	value := formatConsoleValue(a.Value)
+	color := colorFaint
+	isError := key == FieldError || strings.HasPrefix(key, FieldError+".")
+	var text string
+	switch a.Value.Kind() {
+	case slog.KindString:
+		text = a.Value.String()
+	case slog.KindAny:
+		if err, ok := a.Value.Any().(error); ok {
+			text, isError = err.Error(), true
+		}
+	}
+	if isError {
+		color = colorRed
+	}
+	if first, rest, multiLine := strings.Cut(text, "\n"); multiLine && details != nil {
+		value = quoteConsoleValue(first)
+		*details = append(*details, rest)
+	}
+
+	b := bytes.NewBuffer(buf)
+	b.WriteByte(' ')
+	h.colorize(b, color, key+"=")
+	b.WriteString(value)
+	return b.Bytes()
+}
+
+func (h *consoleHandler) colorize(buf *bytes.Buffer, color string, s string) {
+	if !h.opts.Color {
+		buf.WriteString(s)
+		return
+	}
+	buf.WriteString(color)
+	buf.WriteString(s)
+	buf.WriteString(colorReset)
+}
+
+func levelColor(l slog.Level) string {
+	switch {
+	case l >= LevelFatal:
+		return colorMagenta + colorBold
+	case l >= slog.LevelError:
+		return colorRed
+	case l >= slog.LevelWarn:
+		return colorYellow
+	case l >= slog.LevelInfo:
+		return colorGreen
+	case l >= slog.LevelDebug:
+		return colorBlue
+	default:
+		return colorFaint
+	}
+}
+
+func formatConsoleValue(v slog.Value) string {
+	switch v.Kind() {
+	case slog.KindString:
+		return quoteConsoleValue(v.String())
+	case slog.KindTime:
+		return v.Time().Format(time.RFC3339Nano)
+	case slog.KindAny:
+		if err, ok := v.Any().(error); ok {
+			return quoteConsoleValue(err.Error())
+		}
+		return quoteConsoleValue(fmt.Sprintf("%+v", v.Any()))
+	default:
+		return v.String()
+	}
+}
+
+// quoteConsoleValue quotes the value if it is empty, or has spaces, quotes, equal signs or non-printable characters
+func quoteConsoleValue(s string) string {
+	if s == "" || strings.IndexFunc(s, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '"' || r == '=' || !unicode.IsPrint(r)
+	}) >= 0 {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// isTerminal tells whether the writer is a terminal, e.g. the stderr of an application started from a shell
+func isTerminal(w io.Writer) bool {
+	file, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// colorEnabled tells whether the console records written to w are colored.
+// The colors are disabled if the writer is not a terminal, or the NO_COLOR environment variable is set.
+func colorEnabled(w io.Writer) bool {
+	return isTerminal(w) && os.Getenv("NO_COLOR") == ""
+}
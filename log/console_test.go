@@ -0,0 +1,61 @@
+package log_test
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tombenke/go-12f-common/v2/log"
+)
+
+func TestConsoleHandler(t *testing.T) {
+	buf := bytes.Buffer{}
+	logger := slog.New(log.NewConsoleHandler(&buf, &log.ConsoleHandlerOptions{Level: slog.LevelDebug})).
+		With(log.FieldComponent, "Worker", "app", "test")
+
+	logger.Debug("Processing message", "id", 42, "subject", "orders created")
+	logger.WithGroup("req").Info("Done", slog.Group("headers", "accept", "*/*"), "empty", "")
+	logger.Error("Failed", "error", errors.New("captured panic: boom, goroutine 1 [running]:\nmain.main()\n\t/app/main.go:10"))
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	require.Len(t, lines, 5)
+	// The time is followed by the aligned level, the component and the message
+	assert.Regexp(t, `^\d\d:\d\d:\d\d\.\d{3} DEBUG \[Worker\] Processing message app=test id=42 subject="orders created"$`, lines[0])
+	assert.Regexp(t, `^\d\d:\d\d:\d\d\.\d{3} INFO  \[Worker\] Done app=test req\.headers\.accept=\*/\* req\.empty=""$`, lines[1])
+	// The multi-line errors are written below the record indented
+	assert.Regexp(t, `ERROR \[Worker\] Failed app=test error="captured panic: boom, goroutine 1 \[running\]:"$`, lines[2])
+	assert.Equal(t, "    main.main()", lines[3])
+	assert.Equal(t, "    \t/app/main.go:10", lines[4])
+	assert.NotContains(t, buf.String(), "\033[")
+}
+
+func TestConsoleHandlerColor(t *testing.T) {
+	buf := bytes.Buffer{}
+	logger := slog.New(log.NewConsoleHandler(&buf, &log.ConsoleHandlerOptions{Color: true}))
+	logger.Warn("Careful")
+	logger.Debug("Hidden")
+	assert.Contains(t, buf.String(), "\033[33mWARN \033[0m")
+	assert.NotContains(t, buf.String(), "Hidden")
+}
+
+func TestConsoleFormat(t *testing.T) {
+	defer func() { require.NoError(t, log.SetupDefault("info", "text")) }()
+	path := t.TempDir() + "/app.log"
+	outputs, err := log.ParseOutputs([]string{"console:file:" + path, "auto:stderr"}, "json", log.RotationConfig{})
+	require.NoError(t, err)
+	assert.Equal(t, log.FormatConsole, outputs[0].Format)
+	assert.Equal(t, log.FormatAuto, outputs[1].Format)
+	require.NoError(t, log.SetupDefaultOutputs("info", outputs[:1]))
+
+	slog.Info("Starting", log.FieldComponent, "Runner")
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	// The files are not terminals, so they are not colored
+	assert.Contains(t, string(content), "INFO  [Runner] Starting")
+	assert.NotContains(t, string(content), "\033[")
+}
@@ -0,0 +1,150 @@
+package log
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"strings"
+)
+
+const (
+	// The attributes of the error group, see ErrorAttr
+	FieldErrorMessage = "message"
+	FieldErrorType    = "type"
+	FieldErrorChain   = "chain"
+	FieldErrorStack   = "stack"
+
+	// maxStackDepth is the max number of the frames of the captured stack traces
+	maxStackDepth = 64
+)
+
+// ErrorAttr returns with the error attribute, that holds the structured details of the error:
+// `error.message`, `error.type`, the messages of the wrapped errors in `error.chain`,
+// including the members of the joined and multierr errors, and the stack trace captured by WithStack in `error.stack`.
+// It returns with an empty attribute, that the handlers ignore, if err is nil.
+func ErrorAttr(err error) slog.Attr {
+	if err == nil {
+		return slog.Attr{}
+	}
+	return slog.Any(FieldError, ErrorValue(err))
+}
+
+// ErrorValue returns with the structured value of ErrorAttr, e.g. to log the error with a key-value pair
+func ErrorValue(err error) slog.LogValuer {
+	return errorValue{err: err}
+}
+
+// errorValue is the slog.LogValuer of the structured error attribute
+type errorValue struct {
+	err error
+}
+
+func (v errorValue) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.String(FieldErrorMessage, v.err.Error()),
+		slog.String(FieldErrorType, ErrorType(v.err)),
+	}
+	if chain := ErrorChain(v.err); len(chain) > 0 {
+		attrs = append(attrs, slog.Any(FieldErrorChain, chain))
+	}
+	if stack, ok := ErrorStack(v.err); ok {
+		attrs = append(attrs, slog.String(FieldErrorStack, stack))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// ErrorType returns with the type of the cause of the error, that is found by unwrapping it, e.g. *fs.PathError
+func ErrorType(err error) string {
+	for {
+		cause := errors.Unwrap(err)
+		if cause == nil {
+			return fmt.Sprintf("%T", err)
+		}
+		err = cause
+	}
+}
+
+// ErrorChain returns with the messages of the errors wrapped by err depth-first,
+// including the members of the joined and multierr errors
+func ErrorChain(err error) []string {
+	chain := []string{}
+	var walk func(err error)
+	walk = func(err error) {
+		switch wrapper := err.(type) {
+		case interface{ Unwrap() error }:
+			if cause := wrapper.Unwrap(); cause != nil {
+				// The transparent wrappers, e.g. the one of WithStack, are skipped
+				if cause.Error() != err.Error() {
+					chain = append(chain, cause.Error())
+				}
+				walk(cause)
+			}
+		case interface{ Unwrap() []error }:
+			for _, member := range wrapper.Unwrap() {
+				if member != nil {
+					chain = append(chain, member.Error())
+					walk(member)
+				}
+			}
+		}
+	}
+	walk(err)
+	return chain
+}
+
+// stackTracer is an error with the stack trace captured at its creation
+type stackTracer interface {
+	StackTrace() string
+}
+
+// ErrorStack returns with the stack trace of the first error in the chain, that has one, see WithStack
+func ErrorStack(err error) (string, bool) {
+	var tracer stackTracer
+	if errors.As(err, &tracer) {
+		return tracer.StackTrace(), true
+	}
+	return "", false
+}
+
+// stackError is an error with the stack trace captured by WithStack
+type stackError struct {
+	err error
+	pcs []uintptr
+}
+
+// WithStack wraps the error with the stack trace of the caller, that ErrorAttr logs in the `error.stack` attribute.
+// It returns with the error as is if it is nil, or already has a stack trace.
+func WithStack(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := ErrorStack(err); ok {
+		return err
+	}
+	pcs := make([]uintptr, maxStackDepth)
+	n := runtime.Callers(2, pcs)
+	return &stackError{err: err, pcs: pcs[:n]}
+}
+
+func (e *stackError) Error() string {
+	return e.err.Error()
+}
+
+func (e *stackError) Unwrap() error {
+	return e.err
+}
+
+// StackTrace returns with the captured stack trace in the format of debug.Stack, without the goroutine header
+func (e *stackError) StackTrace() string {
+	b := strings.Builder{}
+	frames := runtime.CallersFrames(e.pcs)
+	for {
+		frame, more := frames.Next()
+		fmt.Fprintf(&b, "%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+		if !more {
+			break
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,70 @@
+package log_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tombenke/go-12f-common/v2/log"
+	"go.uber.org/multierr"
+)
+
+func TestErrorAttrJSON(t *testing.T) {
+	buf := bytes.Buffer{}
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	_, cause := os.Open("missing.txt")
+	err := log.WithStack(fmt.Errorf("failed to load the config. %w", cause))
+	logger.Error("Failed", log.ErrorAttr(err))
+
+	record := struct {
+		Error struct {
+			Message string   `json:"message"`
+			Type    string   `json:"type"`
+			Chain   []string `json:"chain"`
+			Stack   string   `json:"stack"`
+		} `json:"error"`
+	}{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "failed to load the config. open missing.txt: no such file or directory", record.Error.Message)
+	assert.Equal(t, "syscall.Errno", record.Error.Type)
+	assert.Equal(t, []string{"open missing.txt: no such file or directory", "no such file or directory"}, record.Error.Chain)
+	assert.Contains(t, record.Error.Stack, "log_test.TestErrorAttrJSON")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
+
+func TestErrorAttrNil(t *testing.T) {
+	buf := bytes.Buffer{}
+	slog.New(slog.NewJSONHandler(&buf, nil)).Info("Checked", log.ErrorAttr(nil))
+
+	assert.NotContains(t, buf.String(), log.FieldError)
+}
+
+func TestErrorChain(t *testing.T) {
+	first, second := errors.New("first"), errors.New("second")
+
+	assert.Empty(t, log.ErrorChain(first))
+	assert.Equal(t, []string{"first", "second"}, log.ErrorChain(errors.Join(first, second)))
+	assert.Equal(t, []string{"first", "second"}, log.ErrorChain(multierr.Combine(first, second)))
+	assert.Equal(t, []string{"first; second", "first", "second"},
+		log.ErrorChain(fmt.Errorf("failed. %w", log.WithStack(multierr.Combine(first, second)))))
+}
+
+func TestWithStack(t *testing.T) {
+	assert.NoError(t, log.WithStack(nil))
+
+	err := log.WithStack(errors.New("boom"))
+	assert.Same(t, err, log.WithStack(err))
+	assert.Equal(t, "boom", err.Error())
+	assert.Equal(t, "*errors.errorString", log.ErrorType(err))
+
+	_, ok := log.ErrorStack(errors.New("boom"))
+	assert.False(t, ok)
+}
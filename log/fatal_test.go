@@ -0,0 +1,35 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCustomLevels(t *testing.T) {
+	buf := bytes.Buffer{}
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: LevelTrace, ReplaceAttr: replaceLevelName}))
+	ctx := NewContext(context.Background(), logger)
+
+	exitCode := -1
+	hookCalled := false
+	exit = func(code int) { exitCode = code }
+	defer func() { exit = os.Exit }()
+	SetFatalHook(func(ctx context.Context) { hookCalled = true })
+	defer SetFatalHook(nil)
+
+	TraceContext(ctx, "trace message")
+	assert.Contains(t, buf.String(), `"level":"TRACE","msg":"trace message"`)
+
+	FatalContext(ctx, "fatal message")
+	assert.Contains(t, buf.String(), `"level":"FATAL","msg":"fatal message"`)
+	assert.True(t, hookCalled)
+	assert.Equal(t, 1, exitCode)
+
+	assert.PanicsWithValue(t, "panic message", func() { PanicContext(ctx, "panic message") })
+	assert.Contains(t, buf.String(), `"level":"PANIC","msg":"panic message"`)
+}
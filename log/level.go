@@ -0,0 +1,178 @@
+package log
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The custom levels beyond the ones of slog
+const (
+	// LevelTrace is more verbose than debug
+	LevelTrace = slog.LevelDebug - 4
+	// LevelFatal logs the error that terminates the application, see FatalContext
+	LevelFatal = slog.LevelError + 4
+	// LevelPanic logs the error that the application panics with, see PanicContext
+	LevelPanic = slog.LevelError + 8
+)
+
+// levelNames are the names of the levels in the log records, and in ParseLevel
+var levelNames = map[slog.Level]string{
+	LevelTrace:      "TRACE",
+	slog.LevelDebug: "DEBUG",
+	slog.LevelInfo:  "INFO",
+	slog.LevelWarn:  "WARN",
+	slog.LevelError: "ERROR",
+	LevelFatal:      "FATAL",
+	LevelPanic:      "PANIC",
+}
+
+var (
+	// level is the level of the default logger, that can be changed at runtime
+	level = new(slog.LevelVar)
+
+	// levelSteps are the levels that StepLevel steps through, from the most verbose to the least verbose one
+	levelSteps = []slog.Level{LevelTrace, slog.LevelDebug, slog.LevelInfo, slog.LevelWarn, slog.LevelError, LevelFatal, LevelPanic}
+
+	// levelRevert holds the state of reverting the runtime level changes to the level set up by SetupDefault
+	levelRevert struct {
+		sync.Mutex
+		base  slog.Level
+		after time.Duration
+		timer *time.Timer
+		at    time.Time
+	}
+)
+
+// ParseLevel returns with the level of the log level name
+func ParseLevel(logLevel string) (slog.Level, error) {
+	switch strings.ToLower(logLevel) {
+	case "panic":
+		return LevelPanic, nil
+	case "fatal":
+		return LevelFatal, nil
+	case "error":
+		return slog.LevelError, nil
+	case "warning", "warn":
+		return slog.LevelWarn, nil
+	case "info":
+		return slog.LevelInfo, nil
+	case "debug":
+		return slog.LevelDebug, nil
+	case "trace":
+		return LevelTrace, nil
+	}
+	return slog.LevelInfo, fmt.Errorf("%w: %s", ErrUnknownLevel, logLevel)
+}
+
+// LevelName returns with the name of the level, e.g. TRACE for LevelTrace.
+// The levels between the named ones are relative to the named level below them, e.g. INFO+2.
+func LevelName(l slog.Level) string {
+	if name, ok := levelNames[l]; ok {
+		return name
+	}
+	base := LevelTrace
+	for named := range levelNames {
+		if named < l && named > base {
+			base = named
+		}
+	}
+	if l < base {
+		return fmt.Sprintf("%s%d", levelNames[base], l-base)
+	}
+	return fmt.Sprintf("%s+%d", levelNames[base], l-base)
+}
+
+// replaceLevelName renders the names of the custom levels in the log records
+func replaceLevelName(groups []string, a slog.Attr) slog.Attr {
+	if a.Key == slog.LevelKey && len(groups) == 0 {
+		if l, ok := a.Value.Any().(slog.Level); ok {
+			a.Value = slog.StringValue(LevelName(l))
+		}
+	}
+	return a
+}
+
+// setBaseLevel sets the level of the default logger, that the runtime level changes revert to
+func setBaseLevel(l slog.Level) {
+	levelRevert.Lock()
+	defer levelRevert.Unlock()
+	levelRevert.base = l
+	stopLevelRevert()
+	level.Set(l)
+}
+
+// SetLevelRevertAfter sets the duration after which the level changed by SetLevel reverts to the level set up by SetupDefault.
+// No revert if it is zero.
+func SetLevelRevertAfter(d time.Duration) {
+	levelRevert.Lock()
+	defer levelRevert.Unlock()
+	levelRevert.after = d
+}
+
+// SetLevel changes the level of the default logger set up by SetupDefault.
+// The level reverts after the duration set by SetLevelRevertAfter.
+func SetLevel(l slog.Level) {
+	levelRevert.Lock()
+	after := levelRevert.after
+	levelRevert.Unlock()
+	SetLevelFor(l, after)
+}
+
+// SetLevelFor changes the level of the default logger, then reverts it to the level set up by SetupDefault after d.
+// No revert if d is zero, or l is the level set up by SetupDefault.
+func SetLevelFor(l slog.Level, d time.Duration) {
+	levelRevert.Lock()
+	defer levelRevert.Unlock()
+	stopLevelRevert()
+	level.Set(l)
+	if d <= 0 || l == levelRevert.base {
+		return
+	}
+	levelRevert.at = time.Now().Add(d)
+	levelRevert.timer = time.AfterFunc(d, func() {
+		levelRevert.Lock()
+		defer levelRevert.Unlock()
+		levelRevert.timer = nil
+		InfoContext(context.Background(), "Reverting log level", "from", LevelName(level.Level()), "to", LevelName(levelRevert.base))
+		level.Set(levelRevert.base)
+	})
+}
+
+// stopLevelRevert cancels the scheduled revert. The caller must hold the lock of levelRevert.
+func stopLevelRevert() {
+	if levelRevert.timer != nil {
+		levelRevert.timer.Stop()
+		levelRevert.timer = nil
+	}
+}
+
+// GetLevel returns with the current level of the default logger set up by SetupDefault
+func GetLevel() slog.Level {
+	return level.Level()
+}
+
+// LevelRevertAt returns with the time when the level of the default logger reverts, or false if no revert is scheduled
+func LevelRevertAt() (time.Time, bool) {
+	levelRevert.Lock()
+	defer levelRevert.Unlock()
+	return levelRevert.at, levelRevert.timer != nil
+}
+
+// StepLevel makes the default logger more verbose if steps is negative, or less verbose if it is positive,
+// then returns with the new level. The level stays within the range of the levels known by ParseLevel.
+func StepLevel(steps int) slog.Level {
+	current := GetLevel()
+	i, found := slices.BinarySearch(levelSteps, current)
+	if !found && steps > 0 {
+		// The current level is between two steps, so the next one up is already one step away
+		i--
+	}
+	i = min(max(i+steps, 0), len(levelSteps)-1)
+	SetLevel(levelSteps[i])
+	return levelSteps[i]
+}
@@ -0,0 +1,87 @@
+package log_test
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tombenke/go-12f-common/v2/log"
+)
+
+func TestStepLevel(t *testing.T) {
+	require.NoError(t, log.SetupDefault("info", "text"))
+	assert.Equal(t, slog.LevelDebug, log.StepLevel(-1))
+	assert.Equal(t, log.LevelTrace, log.StepLevel(-1))
+	assert.Equal(t, log.LevelTrace, log.StepLevel(-1))
+	assert.Equal(t, slog.LevelInfo, log.StepLevel(2))
+	assert.Equal(t, log.LevelPanic, log.StepLevel(10))
+	assert.Equal(t, log.LevelPanic, log.GetLevel())
+}
+
+func TestParseLevel(t *testing.T) {
+	for name, expected := range map[string]slog.Level{
+		"trace":   log.LevelTrace,
+		"DEBUG":   slog.LevelDebug,
+		"info":    slog.LevelInfo,
+		"warning": slog.LevelWarn,
+		"warn":    slog.LevelWarn,
+		"error":   slog.LevelError,
+		"fatal":   log.LevelFatal,
+		"panic":   log.LevelPanic,
+	} {
+		l, err := log.ParseLevel(name)
+		require.NoError(t, err)
+		assert.Equal(t, expected, l, name)
+		// The names of the levels can be parsed back
+		l, err = log.ParseLevel(log.LevelName(l))
+		require.NoError(t, err)
+		assert.Equal(t, expected, l, name)
+	}
+	_, err := log.ParseLevel("verbose")
+	assert.ErrorIs(t, err, log.ErrUnknownLevel)
+}
+
+func TestLevelName(t *testing.T) {
+	assert.Equal(t, "TRACE", log.LevelName(log.LevelTrace))
+	assert.Equal(t, "FATAL", log.LevelName(log.LevelFatal))
+	assert.Equal(t, "PANIC", log.LevelName(log.LevelPanic))
+	assert.Equal(t, "INFO+2", log.LevelName(slog.LevelInfo+2))
+	assert.Equal(t, "TRACE-2", log.LevelName(log.LevelTrace-2))
+	assert.Equal(t, "ERROR+1", log.LevelName(slog.LevelError+1))
+}
+
+func TestSetupDefault(t *testing.T) {
+	require.NoError(t, log.SetupDefault("trace", "json"))
+	assert.Equal(t, log.LevelTrace, log.GetLevel())
+
+	assert.ErrorIs(t, log.SetupDefault("verbose", "json"), log.ErrUnknownLevel)
+	assert.ErrorIs(t, log.SetupDefault("info", "yaml"), log.ErrUnknownFormat)
+	assert.Equal(t, log.LevelTrace, log.GetLevel())
+}
+
+func TestSetLevelFor(t *testing.T) {
+	require.NoError(t, log.SetupDefault("info", "text"))
+	log.SetLevelFor(slog.LevelDebug, 50*time.Millisecond)
+	assert.Equal(t, slog.LevelDebug, log.GetLevel())
+	_, scheduled := log.LevelRevertAt()
+	assert.True(t, scheduled)
+
+	require.Eventually(t, func() bool {
+		return log.GetLevel() == slog.LevelInfo
+	}, time.Second, 10*time.Millisecond)
+	_, scheduled = log.LevelRevertAt()
+	assert.False(t, scheduled)
+}
+
+func TestSetLevelRevertAfter(t *testing.T) {
+	require.NoError(t, log.SetupDefault("warn", "text"))
+	log.SetLevelRevertAfter(50 * time.Millisecond)
+	defer log.SetLevelRevertAfter(0)
+
+	log.SetLevel(slog.LevelDebug)
+	require.Eventually(t, func() bool {
+		return log.GetLevel() == slog.LevelWarn
+	}, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,296 @@
+// Package logtest provides a slog.Handler that captures the log records in the tests,
+// with helpers to query and assert them.
+//
+//	func TestWorker(t *testing.T) {
+//		recorder := logtest.New(t)
+//		ctx := recorder.NewContext(context.Background())
+//		worker.Process(ctx, msg)
+//		logtest.AssertLogged(t, recorder, logtest.Level(slog.LevelInfo), logtest.Message("Processed"), logtest.Attr("id", 42))
+//	}
+package logtest
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tombenke/go-12f-common/v2/log"
+)
+
+// Record is a captured log record
+type Record struct {
+	Time    time.Time
+	Level   slog.Level
+	Message string
+	// Attrs are the resolved attributes of the logger and the record, the keys of the groups are joined by dots, e.g. req.id
+	Attrs map[string]slog.Value
+}
+
+// Attr returns with the value of the attribute, or false if the record has no such attribute
+func (r Record) Attr(key string) (slog.Value, bool) {
+	value, ok := r.Attrs[key]
+	return value, ok
+}
+
+// String formats the record like the text handler, e.g. `INFO msg="Processed" id=42`
+func (r Record) String() string {
+	b := strings.Builder{}
+	fmt.Fprintf(&b, "%s msg=%q", log.LevelName(r.Level), r.Message)
+	for _, key := range slices.Sorted(maps.Keys(r.Attrs)) {
+		fmt.Fprintf(&b, " %s=%v", key, r.Attrs[key])
+	}
+	return b.String()
+}
+
+// Recorder captures the records of all levels logged by its handler
+type Recorder struct {
+	mu      sync.Mutex
+	records []Record
+}
+
+// NewRecorder creates a recorder without test integration, see New
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// New creates a recorder, that logs the captured records by t.Log if the test fails
+func New(t testing.TB) *Recorder {
+	t.Helper()
+	r := NewRecorder()
+	t.Cleanup(func() {
+		if t.Failed() {
+			for _, record := range r.Records() {
+				t.Log(record.String())
+			}
+		}
+	})
+	return r
+}
+
+// Handler returns with a handler that captures the records into the recorder
+func (r *Recorder) Handler() slog.Handler {
+	return &handler{recorder: r}
+}
+
+// Logger returns with a logger that captures the records into the recorder
+func (r *Recorder) Logger() *slog.Logger {
+	return slog.New(r.Handler())
+}
+
+// NewContext returns with a child of ctx that carries the logger of the recorder, see log.NewContext
+func (r *Recorder) NewContext(ctx context.Context) context.Context {
+	return log.NewContext(ctx, r.Logger())
+}
+
+// SetDefault makes the logger of the recorder the default logger until the end of the test.
+// The tests using it must not run in parallel.
+func (r *Recorder) SetDefault(t testing.TB) {
+	previous := slog.Default()
+	slog.SetDefault(r.Logger())
+	t.Cleanup(func() { slog.SetDefault(previous) })
+}
+
+// Records returns with the captured records in the order they were logged
+func (r *Recorder) Records() []Record {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.records)
+}
+
+// Filter returns with the captured records that match all the matchers
+func (r *Recorder) Filter(matchers ...Matcher) []Record {
+	filtered := []Record{}
+	for _, record := range r.Records() {
+		if matchAll(record, matchers) {
+			filtered = append(filtered, record)
+		}
+	}
+	return filtered
+}
+
+// Find returns with the first captured record that matches all the matchers, or false if there is none
+func (r *Recorder) Find(matchers ...Matcher) (Record, bool) {
+	for _, record := range r.Records() {
+		if matchAll(record, matchers) {
+			return record, true
+		}
+	}
+	return Record{}, false
+}
+
+// Count returns with the number of the captured records that match all the matchers
+func (r *Recorder) Count(matchers ...Matcher) int {
+	return len(r.Filter(matchers...))
+}
+
+// Reset drops the captured records
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records = nil
+}
+
+func (r *Recorder) add(record Record) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records = append(r.records, record)
+}
+
+// Matcher selects the records
+type Matcher func(Record) bool
+
+// Level matches the records of the level
+func Level(l slog.Level) Matcher {
+	return func(r Record) bool { return r.Level == l }
+}
+
+// MinLevel matches the records at or above the level
+func MinLevel(l slog.Level) Matcher {
+	return func(r Record) bool { return r.Level >= l }
+}
+
+// Message matches the records with the message
+func Message(msg string) Matcher {
+	return func(r Record) bool { return r.Message == msg }
+}
+
+// MessageContains matches the records with a message that contains the substring
+func MessageContains(substr string) Matcher {
+	return func(r Record) bool { return strings.Contains(r.Message, substr) }
+}
+
+// HasAttr matches the records with the attribute
+func HasAttr(key string) Matcher {
+	return func(r Record) bool {
+		_, ok := r.Attrs[key]
+		return ok
+	}
+}
+
+// Attr matches the records with the attribute of the value, e.g. Attr("id", 42) matches both int and int64 values
+func Attr(key string, value any) Matcher {
+	expected := slog.AnyValue(value).Resolve()
+	return func(r Record) bool {
+		actual, ok := r.Attrs[key]
+		if !ok || actual.Kind() != expected.Kind() {
+			return false
+		}
+		if actual.Kind() == slog.KindAny {
+			return reflect.DeepEqual(actual.Any(), expected.Any())
+		}
+		return actual.Equal(expected)
+	}
+}
+
+// AssertLogged asserts that a record matching all the matchers has been captured
+func AssertLogged(t assert.TestingT, r *Recorder, matchers ...Matcher) bool {
+	if h, ok := t.(interface{ Helper() }); ok {
+		h.Helper()
+	}
+	if _, ok := r.Find(matchers...); !ok {
+		return assert.Fail(t, "No matching log record", "Captured records:\n%s", formatRecords(r.Records()))
+	}
+	return true
+}
+
+// AssertNotLogged asserts that no record matching all the matchers has been captured
+func AssertNotLogged(t assert.TestingT, r *Recorder, matchers ...Matcher) bool {
+	if h, ok := t.(interface{ Helper() }); ok {
+		h.Helper()
+	}
+	if matching := r.Filter(matchers...); len(matching) > 0 {
+		return assert.Fail(t, "Unexpected matching log records", "Matching records:\n%s", formatRecords(matching))
+	}
+	return true
+}
+
+// RequireLogged requires a record matching all the matchers, and returns with the first one
+func RequireLogged(t require.TestingT, r *Recorder, matchers ...Matcher) Record {
+	if h, ok := t.(interface{ Helper() }); ok {
+		h.Helper()
+	}
+	record, ok := r.Find(matchers...)
+	if !ok {
+		require.Fail(t, "No matching log record", "Captured records:\n%s", formatRecords(r.Records()))
+	}
+	return record
+}
+
+func matchAll(record Record, matchers []Matcher) bool {
+	for _, matcher := range matchers {
+		if !matcher(record) {
+			return false
+		}
+	}
+	return true
+}
+
+func formatRecords(records []Record) string {
+	lines := make([]string, len(records))
+	for i, record := range records {
+		lines[i] = record.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// handler is the slog.Handler of a recorder
+type handler struct {
+	recorder *Recorder
+	attrs    map[string]slog.Value
+	groups   []string
+}
+
+func (h *handler) Enabled(ctx context.Context, l slog.Level) bool {
+	return true
+}
+
+func (h *handler) Handle(ctx context.Context, record slog.Record) error {
+	attrs := make(map[string]slog.Value, len(h.attrs)+record.NumAttrs())
+	maps.Copy(attrs, h.attrs)
+	record.Attrs(func(a slog.Attr) bool {
+		addAttr(attrs, h.groups, a)
+		return true
+	})
+	h.recorder.add(Record{Time: record.Time, Level: record.Level, Message: record.Message, Attrs: attrs})
+	return nil
+}
+
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	child := &handler{recorder: h.recorder, attrs: make(map[string]slog.Value, len(h.attrs)+len(attrs)), groups: h.groups}
+	maps.Copy(child.attrs, h.attrs)
+	for _, a := range attrs {
+		addAttr(child.attrs, h.groups, a)
+	}
+	return child
+}
+
+func (h *handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &handler{recorder: h.recorder, attrs: h.attrs, groups: append(slices.Clone(h.groups), name)}
+}
+
+// addAttr adds the resolved attribute to attrs, and flattens the groups
+func addAttr(attrs map[string]slog.Value, groups []string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			groups = append(slices.Clone(groups), a.Key)
+		}
+		for _, attr := range a.Value.Group() {
+			addAttr(attrs, groups, attr)
+		}
+		return
+	}
+	attrs[strings.Join(append(slices.Clone(groups), a.Key), ".")] = a.Value
+}
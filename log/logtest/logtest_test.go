@@ -0,0 +1,63 @@
+package logtest_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tombenke/go-12f-common/v2/log"
+	"github.com/tombenke/go-12f-common/v2/log/logtest"
+)
+
+// failingT records the failures of the assertions instead of failing the test
+type failingT struct {
+	failed bool
+}
+
+func (t *failingT) Errorf(format string, args ...any) { t.failed = true }
+func (t *failingT) FailNow()                          { t.failed = true }
+
+func TestRecorder(t *testing.T) {
+	recorder := logtest.New(t)
+	ctx := recorder.NewContext(context.Background())
+	ctx, _ = log.With(ctx, log.FieldComponent, "Worker")
+
+	log.InfoContext(ctx, "Processed message", "id", 42, slog.Group("req", "path", "/orders"))
+	log.DebugContext(ctx, "Processing details")
+	log.ErrorContext(ctx, "Failed to process message", log.FieldError, errors.New("timeout"))
+
+	require.Len(t, recorder.Records(), 3)
+	record := logtest.RequireLogged(t, recorder, logtest.Message("Processed message"))
+	assert.Equal(t, slog.LevelInfo, record.Level)
+	assert.Equal(t, `INFO msg="Processed message" component=Worker id=42 req.path=/orders`, record.String())
+
+	logtest.AssertLogged(t, recorder, logtest.Level(slog.LevelInfo), logtest.Attr("id", 42), logtest.Attr("req.path", "/orders"))
+	logtest.AssertLogged(t, recorder, logtest.MessageContains("Failed"), logtest.HasAttr(log.FieldError), logtest.Attr(log.FieldComponent, "Worker"))
+	logtest.AssertNotLogged(t, recorder, logtest.MinLevel(slog.LevelWarn), logtest.MessageContains("Processed"))
+	assert.Equal(t, 2, recorder.Count(logtest.MessageContains("Process")))
+
+	value, ok := record.Attr("id")
+	require.True(t, ok)
+	assert.Equal(t, int64(42), value.Int64())
+
+	// The failed assertions report the captured records
+	failing := &failingT{}
+	assert.False(t, logtest.AssertLogged(failing, recorder, logtest.Message("Unknown")))
+	assert.True(t, failing.failed)
+	failing = &failingT{}
+	assert.False(t, logtest.AssertNotLogged(failing, recorder, logtest.Level(slog.LevelDebug)))
+	assert.True(t, failing.failed)
+
+	recorder.Reset()
+	assert.Empty(t, recorder.Records())
+}
+
+func TestRecorderSetDefault(t *testing.T) {
+	recorder := logtest.New(t)
+	recorder.SetDefault(t)
+	log.WarnContext(context.Background(), "To the default logger")
+	logtest.AssertLogged(t, recorder, logtest.Level(slog.LevelWarn), logtest.Message("To the default logger"))
+}
@@ -0,0 +1,73 @@
+package log
+
+import (
+	"context"
+	"sync/atomic"
+
+	otellog "go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/embedded"
+)
+
+// otelProvider holds the LoggerProvider, that the records of the default logger are bridged to. Nothing is bridged if nil.
+var otelProvider atomic.Pointer[otelProviderHolder]
+
+type otelProviderHolder struct {
+	provider otellog.LoggerProvider
+}
+
+// SetOtelLoggerProvider sets the OTEL LoggerProvider, that the records of the default logger are bridged to.
+// It applies to the loggers created before too. Setting nil stops bridging, e.g. before the provider is shut down.
+func SetOtelLoggerProvider(provider otellog.LoggerProvider) {
+	if provider == nil {
+		otelProvider.Store(nil)
+		return
+	}
+	otelProvider.Store(&otelProviderHolder{provider: provider})
+}
+
+// swappableLoggerProvider provides loggers, that emit by the provider set by SetOtelLoggerProvider at the time of the emit
+type swappableLoggerProvider struct {
+	embedded.LoggerProvider
+}
+
+func (swappableLoggerProvider) Logger(name string, options ...otellog.LoggerOption) otellog.Logger {
+	return &swappableLogger{name: name, options: options}
+}
+
+// swappableLogger emits by the logger of the current provider, that it caches until the provider changes
+type swappableLogger struct {
+	embedded.Logger
+	name    string
+	options []otellog.LoggerOption
+	cached  atomic.Pointer[providerLogger]
+}
+
+type providerLogger struct {
+	holder *otelProviderHolder
+	logger otellog.Logger
+}
+
+// logger returns with the logger of the current provider, or nil if there is no provider
+func (l *swappableLogger) logger() otellog.Logger {
+	holder := otelProvider.Load()
+	if holder == nil {
+		return nil
+	}
+	if cached := l.cached.Load(); cached != nil && cached.holder == holder {
+		return cached.logger
+	}
+	logger := holder.provider.Logger(l.name, l.options...)
+	l.cached.Store(&providerLogger{holder: holder, logger: logger})
+	return logger
+}
+
+func (l *swappableLogger) Emit(ctx context.Context, record otellog.Record) {
+	if logger := l.logger(); logger != nil {
+		logger.Emit(ctx, record)
+	}
+}
+
+func (l *swappableLogger) Enabled(ctx context.Context, param otellog.EnabledParameters) bool {
+	logger := l.logger()
+	return logger != nil && logger.Enabled(ctx, param)
+}
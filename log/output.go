@@ -0,0 +1,156 @@
+package log
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+)
+
+const (
+	FormatJSON    = "json"
+	FormatText    = "text"
+	FormatConsole = "console"
+	// FormatAuto selects the console format if the output is a terminal, otherwise the JSON format
+	FormatAuto = "auto"
+
+	OutputStderr = "stderr"
+	OutputStdout = "stdout"
+	// OutputFilePrefix is the prefix of the file outputs, e.g. file:/var/log/app.log
+	OutputFilePrefix = "file:"
+)
+
+var (
+	// ErrUnknownOutput is returned by ParseOutputs for an unknown output
+	ErrUnknownOutput = errors.New("unknown log output")
+
+	// files are the log files of the default logger, that ReopenOutputs reopens
+	files   []*RotatingFile
+	filesMu sync.Mutex
+)
+
+// Output is a sink of the default logger, that writes the records in the given format
+type Output struct {
+	Format string
+	Writer io.Writer
+}
+
+// ParseOutputs parses the outputs in the `[format:]target` form, where the target is `stderr`, `stdout` or `file:<path>`,
+// and the optional format is `json`, `text`, `console` or `auto`, e.g. `json:file:/var/log/app.log`, `text:stdout`.
+// The outputs without format use defaultFormat. The files are opened with the rotation config.
+func ParseOutputs(specs []string, defaultFormat string, rotation RotationConfig) ([]Output, error) {
+	outputs := make([]Output, 0, len(specs))
+	var errs error
+	for _, spec := range specs {
+		spec = strings.TrimSpace(spec)
+		output := Output{Format: defaultFormat}
+		if format, target, found := strings.Cut(spec, ":"); found && isFormat(format) {
+			output.Format, spec = strings.ToLower(format), target
+		}
+		switch {
+		case strings.EqualFold(spec, OutputStderr):
+			output.Writer = os.Stderr
+		case strings.EqualFold(spec, OutputStdout):
+			output.Writer = os.Stdout
+		case strings.HasPrefix(spec, OutputFilePrefix) && len(spec) > len(OutputFilePrefix):
+			file, err := OpenRotatingFile(strings.TrimPrefix(spec, OutputFilePrefix), rotation)
+			if err != nil {
+				errs = errors.Join(errs, err)
+				continue
+			}
+			output.Writer = file
+		default:
+			errs = errors.Join(errs, fmt.Errorf("%w: %s", ErrUnknownOutput, spec))
+			continue
+		}
+		outputs = append(outputs, output)
+	}
+	if errs != nil {
+		closeOutputs(outputs)
+		return nil, errs
+	}
+	return outputs, nil
+}
+
+// SetupDefaultOutputs sets up the default logger with the given level, that writes the records to all the outputs.
+// It returns with error and leaves the default logger unchanged if the level or a format is unknown.
+// The log files of the previous outputs are closed.
+func SetupDefaultOutputs(logLevel string, outputs []Output) error {
+	leveler, err := ParseLevel(logLevel)
+	if err != nil {
+		return err
+	}
+	if len(outputs) == 0 {
+		return fmt.Errorf("%w: no outputs", ErrUnknownOutput)
+	}
+	slogHandlerOptions := &slog.HandlerOptions{
+		Level:       level,
+		ReplaceAttr: replaceLevelName,
+	}
+	handlers := make([]slog.Handler, 0, len(outputs))
+	for _, output := range outputs {
+		switch strings.ToLower(output.Format) {
+		case FormatJSON:
+			handlers = append(handlers, slog.NewJSONHandler(output.Writer, slogHandlerOptions))
+		case FormatText:
+			handlers = append(handlers, slog.NewTextHandler(output.Writer, slogHandlerOptions))
+		case FormatConsole:
+			handlers = append(handlers, NewConsoleHandler(output.Writer, &ConsoleHandlerOptions{Level: level, Color: colorEnabled(output.Writer)}))
+		case FormatAuto:
+			if isTerminal(output.Writer) {
+				handlers = append(handlers, NewConsoleHandler(output.Writer, &ConsoleHandlerOptions{Level: level, Color: colorEnabled(output.Writer)}))
+			} else {
+				handlers = append(handlers, slog.NewJSONHandler(output.Writer, slogHandlerOptions))
+			}
+		default:
+			return fmt.Errorf("%w: %s", ErrUnknownFormat, output.Format)
+		}
+	}
+	setBaseLevel(leveler)
+	setDefaultHandler(NewTeeHandler(handlers[0], handlers[1:]...))
+
+	filesMu.Lock()
+	defer filesMu.Unlock()
+	previous := files
+	files = nil
+	for _, output := range outputs {
+		if file, ok := output.Writer.(*RotatingFile); ok {
+			files = append(files, file)
+		}
+	}
+	for _, file := range previous {
+		if !slices.Contains(files, file) {
+			_ = file.Close()
+		}
+	}
+	return nil
+}
+
+// ReopenOutputs reopens the log files of the default logger, e.g. after they have been moved by an external logrotate
+func ReopenOutputs() error {
+	filesMu.Lock()
+	defer filesMu.Unlock()
+	var errs error
+	for _, file := range files {
+		if err := file.Reopen(); err != nil {
+			errs = errors.Join(errs, fmt.Errorf("failed to reopen %s. %w", file.Path(), err))
+		}
+	}
+	return errs
+}
+
+func isFormat(format string) bool {
+	return slices.Contains([]string{FormatJSON, FormatText, FormatConsole, FormatAuto}, strings.ToLower(format))
+}
+
+func closeOutputs(outputs []Output) {
+	for _, output := range outputs {
+		if file, ok := output.Writer.(*RotatingFile); ok {
+			_ = file.Close()
+		}
+	}
+}
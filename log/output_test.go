@@ -0,0 +1,66 @@
+package log_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tombenke/go-12f-common/v2/log"
+)
+
+func TestParseOutputs(t *testing.T) {
+	dir := t.TempDir()
+	outputs, err := log.ParseOutputs([]string{"stderr", "text:stdout", "json:file:" + filepath.Join(dir, "app.log")}, "text", log.RotationConfig{})
+	require.NoError(t, err)
+	require.Len(t, outputs, 3)
+	assert.Equal(t, log.Output{Format: "text", Writer: os.Stderr}, outputs[0])
+	assert.Equal(t, log.Output{Format: "text", Writer: os.Stdout}, outputs[1])
+	assert.Equal(t, "json", outputs[2].Format)
+	require.IsType(t, &log.RotatingFile{}, outputs[2].Writer)
+	require.NoError(t, outputs[2].Writer.(*log.RotatingFile).Close())
+
+	_, err = log.ParseOutputs([]string{"stderr", "syslog"}, "json", log.RotationConfig{})
+	assert.ErrorIs(t, err, log.ErrUnknownOutput)
+	_, err = log.ParseOutputs([]string{"file:"}, "json", log.RotationConfig{})
+	assert.ErrorIs(t, err, log.ErrUnknownOutput)
+}
+
+func TestSetupDefaultOutputs(t *testing.T) {
+	defer func() { require.NoError(t, log.SetupDefault("info", "text")) }()
+	dir := t.TempDir()
+	jsonPath := filepath.Join(dir, "app.json")
+	textPath := filepath.Join(dir, "app.txt")
+	outputs, err := log.ParseOutputs([]string{"file:" + jsonPath, "text:file:" + textPath}, "json", log.RotationConfig{})
+	require.NoError(t, err)
+	assert.ErrorIs(t, log.SetupDefaultOutputs("info", []log.Output{{Format: "xml", Writer: os.Stderr}}), log.ErrUnknownFormat)
+	require.NoError(t, log.SetupDefaultOutputs("info", outputs))
+
+	log.InfoContext(context.Background(), "to both", "password", "pass")
+	log.DebugContext(context.Background(), "to none")
+
+	content, err := os.ReadFile(jsonPath)
+	require.NoError(t, err)
+	record := map[string]any{}
+	require.NoError(t, json.Unmarshal(content, &record))
+	assert.Equal(t, "to both", record["msg"])
+	assert.Equal(t, log.RedactedValue, record["password"])
+
+	content, err = os.ReadFile(textPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `msg="to both" password=[REDACTED]`)
+	assert.NotContains(t, string(content), "to none")
+
+	// The files are reopened after an external logrotate moved them
+	require.NoError(t, os.Rename(textPath, textPath+".1"))
+	require.NoError(t, log.ReopenOutputs())
+	log.InfoContext(context.Background(), "after reopen")
+	content, err = os.ReadFile(textPath)
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(content), "\n"))
+	assert.Contains(t, string(content), `msg="after reopen"`)
+}
@@ -0,0 +1,356 @@
+package log
+
+import (
+	"context"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	// RedactedValue replaces the sensitive values in the log records
+	RedactedValue = "[REDACTED]"
+
+	// redactTag is the struct tag that marks the fields to redact, e.g. `log:"redact"`
+	redactTag = "log"
+)
+
+var (
+	// DefaultRedactKeys are the default patterns of the sensitive keys, see SetRedactKeys
+	DefaultRedactKeys = []string{"password", "passwd", "token", "secret", "authorization", "apikey", "api_key"}
+
+	redactKeys atomic.Pointer[[]string]
+
+	// redactTypes caches whether the values of a struct type need redaction
+	redactTypes sync.Map
+)
+
+func init() {
+	SetRedactKeys(DefaultRedactKeys)
+}
+
+// SetRedactKeys sets the patterns of the sensitive keys. The values of the attributes, struct fields and map entries
+// are redacted if their key contains any of the patterns, case-insensitively.
+func SetRedactKeys(patterns []string) {
+	lowered := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		if pattern = strings.ToLower(strings.TrimSpace(pattern)); pattern != "" {
+			lowered = append(lowered, pattern)
+		}
+	}
+	redactKeys.Store(&lowered)
+	redactTypes.Clear()
+}
+
+// isSensitiveKey tells whether the key matches any of the patterns set by SetRedactKeys
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, pattern := range *redactKeys.Load() {
+		if strings.Contains(key, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// secret is a value that is never logged
+type secret struct {
+	value any
+}
+
+// Secret wraps the value, so it is logged as RedactedValue, e.g. `log.InfoContext(ctx, "Connecting", "dsn", log.Secret(dsn))`
+func Secret(value any) slog.LogValuer {
+	return secret{value: value}
+}
+
+func (s secret) LogValue() slog.Value {
+	return slog.StringValue(RedactedValue)
+}
+
+func (s secret) String() string {
+	return RedactedValue
+}
+
+func (s secret) MarshalJSON() ([]byte, error) {
+	return json.Marshal(RedactedValue)
+}
+
+// Redact returns with a copy of the value in which the sensitive data is replaced by RedactedValue,
+// or the value itself if it holds no sensitive data. The structs and maps are returned as maps, the slices and arrays as []any.
+// The struct fields are sensitive if they are tagged by `log:"redact"`, or their names match the patterns of SetRedactKeys.
+func Redact(value any) any {
+	if redacted, ok := redactValue(reflect.ValueOf(value)); ok {
+		return redacted
+	}
+	return value
+}
+
+// RedactURL returns with the URL as a string, in which the password of the user info,
+// and the values of the query parameters whose keys match the patterns of SetRedactKeys are redacted
+func RedactURL(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	if u.RawQuery != "" {
+		redacted := *u
+		params := strings.Split(u.RawQuery, "&")
+		for i, param := range params {
+			rawKey, _, hasValue := strings.Cut(param, "=")
+			key, err := url.QueryUnescape(rawKey)
+			if err != nil {
+				key = rawKey
+			}
+			if hasValue && isSensitiveKey(key) {
+				params[i] = rawKey + "=" + url.QueryEscape(RedactedValue)
+			}
+		}
+		redacted.RawQuery = strings.Join(params, "&")
+		u = &redacted
+	}
+	return u.Redacted()
+}
+
+// RedactAttr replaces the sensitive values of the attribute by RedactedValue
+func RedactAttr(a slog.Attr) slog.Attr {
+	if isSensitiveKey(a.Key) {
+		return slog.String(a.Key, RedactedValue)
+	}
+	switch a.Value.Kind() {
+	case slog.KindLogValuer:
+		a.Value = a.Value.Resolve()
+		return RedactAttr(a)
+	case slog.KindGroup:
+		group := a.Value.Group()
+		redacted := make([]slog.Attr, len(group))
+		for i, attr := range group {
+			redacted[i] = RedactAttr(attr)
+		}
+		a.Value = slog.GroupValue(redacted...)
+	case slog.KindAny:
+		if redacted, ok := redactValue(reflect.ValueOf(a.Value.Any())); ok {
+			a.Value = slog.AnyValue(redacted)
+		}
+	}
+	return a
+}
+
+// redactValue returns with the redacted copy of the struct, map, slice or array value, and true if it holds sensitive data
+func redactValue(v reflect.Value) (any, bool) {
+	for v.IsValid() && (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) && !v.IsNil() {
+		if hasOwnFormat(v.Type()) {
+			return nil, false
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() || hasOwnFormat(v.Type()) {
+		return nil, false
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		if !structNeedsRedaction(v.Type()) {
+			return nil, false
+		}
+		redacted := make(map[string]any, v.NumField())
+		redactStruct(v, redacted)
+		return redacted, true
+
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil, false
+		}
+		redacted := make(map[string]any, v.Len())
+		found := false
+		for iter := v.MapRange(); iter.Next(); {
+			key := iter.Key().String()
+			if isSensitiveKey(key) {
+				redacted[key] = RedactedValue
+				found = true
+			} else if value, ok := redactValue(iter.Value()); ok {
+				redacted[key] = value
+				found = true
+			} else {
+				redacted[key] = iter.Value().Interface()
+			}
+		}
+		return redacted, found
+
+	case reflect.Slice, reflect.Array:
+		if !typeNeedsRedaction(v.Type().Elem()) {
+			return nil, false
+		}
+		redacted := make([]any, v.Len())
+		found := false
+		for i := range v.Len() {
+			if value, ok := redactValue(v.Index(i)); ok {
+				redacted[i] = value
+				found = true
+			} else {
+				redacted[i] = v.Index(i).Interface()
+			}
+		}
+		return redacted, found
+	}
+	return nil, false
+}
+
+// redactStruct puts the fields of the struct value into the map, with the sensitive values redacted.
+// The embedded structs are flattened the same way as encoding/json does.
+func redactStruct(v reflect.Value, redacted map[string]any) {
+	for i := range v.NumField() {
+		field := v.Type().Field(i)
+		if !isVisibleField(field) {
+			continue
+		}
+		name := fieldName(field)
+		if name == "-" {
+			continue
+		}
+		fieldValue := v.Field(i)
+		if field.Anonymous && field.Tag.Get("json") == "" && !isSensitiveField(field) {
+			if fieldValue.Kind() == reflect.Pointer && !fieldValue.IsNil() {
+				fieldValue = fieldValue.Elem()
+			}
+			if fieldValue.Kind() == reflect.Struct && !hasOwnFormat(fieldValue.Type()) {
+				redactStruct(fieldValue, redacted)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if isSensitiveField(field) {
+			redacted[name] = RedactedValue
+		} else if value, ok := redactValue(fieldValue); ok {
+			redacted[name] = value
+		} else {
+			redacted[name] = fieldValue.Interface()
+		}
+	}
+}
+
+// structNeedsRedaction tells whether the struct type has sensitive fields, directly or in its nested structs
+func structNeedsRedaction(t reflect.Type) bool {
+	if cached, ok := redactTypes.Load(t); ok {
+		return cached.(bool)
+	}
+	// Assume no redaction while visiting the type, to stop at recursive types
+	redactTypes.Store(t, false)
+	needs := false
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !isVisibleField(field) {
+			continue
+		}
+		if isSensitiveField(field) || typeNeedsRedaction(field.Type) {
+			needs = true
+			break
+		}
+	}
+	redactTypes.Store(t, needs)
+	return needs
+}
+
+// typeNeedsRedaction tells whether the values of the type may hold sensitive data
+func typeNeedsRedaction(t reflect.Type) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if hasOwnFormat(t) {
+		return false
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		return structNeedsRedaction(t)
+	case reflect.Map, reflect.Interface:
+		// The keys and the dynamic types are only known at runtime
+		return true
+	case reflect.Slice, reflect.Array:
+		return typeNeedsRedaction(t.Elem())
+	}
+	return false
+}
+
+// isVisibleField tells whether encoding/json marshals the field, or the fields of the embedded struct
+func isVisibleField(field reflect.StructField) bool {
+	if field.Anonymous {
+		t := field.Type
+		if t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		return field.IsExported() || t.Kind() == reflect.Struct
+	}
+	return field.IsExported()
+}
+
+// isSensitiveField tells whether the struct field is tagged by `log:"redact"`, or its name is sensitive
+func isSensitiveField(field reflect.StructField) bool {
+	return field.Tag.Get(redactTag) == "redact" || isSensitiveKey(field.Name)
+}
+
+// fieldName returns with the JSON name of the field, or the name of the field if it has no JSON name
+func fieldName(field reflect.StructField) string {
+	if name, _, _ := strings.Cut(field.Tag.Get("json"), ","); name != "" {
+		return name
+	}
+	return field.Name
+}
+
+var (
+	logValuerType     = reflect.TypeFor[slog.LogValuer]()
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+	stringerType      = reflect.TypeFor[fmt.Stringer]()
+	errorType         = reflect.TypeFor[error]()
+)
+
+// hasOwnFormat tells whether the type controls its own format in the logs, so it is not redacted field by field
+func hasOwnFormat(t reflect.Type) bool {
+	for _, i := range []reflect.Type{logValuerType, jsonMarshalerType, textMarshalerType, stringerType, errorType} {
+		if t.Implements(i) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactHandler is a slog.Handler middleware, that replaces the sensitive values of the attributes by RedactedValue
+type redactHandler struct {
+	next slog.Handler
+}
+
+// NewRedactHandler wraps the handler to redact the sensitive values of the attributes, see RedactAttr
+func NewRedactHandler(next slog.Handler) slog.Handler {
+	return &redactHandler{next: next}
+}
+
+func (h *redactHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	return h.next.Enabled(ctx, l)
+}
+
+func (h *redactHandler) Handle(ctx context.Context, record slog.Record) error {
+	redacted := slog.NewRecord(record.Time, record.Level, record.Message, record.PC)
+	record.Attrs(func(a slog.Attr) bool {
+		redacted.AddAttrs(RedactAttr(a))
+		return true
+	})
+	return h.next.Handle(ctx, redacted)
+}
+
+func (h *redactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		redacted[i] = RedactAttr(a)
+	}
+	return &redactHandler{next: h.next.WithAttrs(redacted)}
+}
+
+func (h *redactHandler) WithGroup(name string) slog.Handler {
+	return &redactHandler{next: h.next.WithGroup(name)}
+}
@@ -0,0 +1,248 @@
+package log
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// recentLogs is the ring buffer of the recent records of the default logger, see SetupRecentLogs
+var recentLogs atomic.Pointer[recentLogsBuffer]
+
+type recentLogsBuffer struct {
+	buffer *LevelRingBuffer
+	level  slog.Level
+}
+
+// RingBuffer keeps the last records written to it, e.g. by a slog.JSONHandler
+type RingBuffer struct {
+	mu      sync.Mutex
+	records []ringEntry
+	next    int
+	full    bool
+	// seq orders the records, it may be shared by several buffers, see LevelRingBuffer
+	seq *atomic.Uint64
+}
+
+// ringEntry is a record in the ring buffer, with its sequence number
+type ringEntry struct {
+	seq    uint64
+	record json.RawMessage
+}
+
+// NewRingBuffer creates a ring buffer that keeps the last size records
+func NewRingBuffer(size int) *RingBuffer {
+	return newRingBuffer(size, &atomic.Uint64{})
+}
+
+func newRingBuffer(size int, seq *atomic.Uint64) *RingBuffer {
+	return &RingBuffer{records: make([]ringEntry, max(size, 1)), seq: seq}
+}
+
+// Write stores a copy of p as a record, and overwrites the oldest one if the buffer is full
+func (b *RingBuffer) Write(p []byte) (int, error) {
+	record := json.RawMessage(slices.Clone(p[:len(p)-countTrailingNewlines(p)]))
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.records[b.next] = ringEntry{seq: b.seq.Add(1), record: record}
+	b.next = (b.next + 1) % len(b.records)
+	b.full = b.full || b.next == 0
+	return len(p), nil
+}
+
+// Records returns with the records in the buffer, the oldest first
+func (b *RingBuffer) Records() []json.RawMessage {
+	return recordsOf(b.entries())
+}
+
+// entries returns with the entries in the buffer, the oldest first
+func (b *RingBuffer) entries() []ringEntry {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.full {
+		return slices.Clone(b.records[:b.next])
+	}
+	return append(slices.Clone(b.records[b.next:]), b.records[:b.next]...)
+}
+
+// Dump writes the records in the buffer to w as JSON lines, the oldest first
+func (b *RingBuffer) Dump(w io.Writer) error {
+	return dumpRecords(w, b.Records())
+}
+
+// LevelRingBuffer keeps the last records below WARN and the last WARN or higher level records in separate ring buffers,
+// so a burst of low level records does not evict the warnings and errors
+type LevelRingBuffer struct {
+	low  *RingBuffer
+	high *RingBuffer
+}
+
+// NewLevelRingBuffer creates a ring buffer that keeps the last size records below WARN, and the last warnSize WARN or higher level records.
+// The records of all the levels share the size records if warnSize is 0.
+func NewLevelRingBuffer(size int, warnSize int) *LevelRingBuffer {
+	seq := &atomic.Uint64{}
+	b := &LevelRingBuffer{low: newRingBuffer(size, seq)}
+	if warnSize > 0 {
+		b.high = newRingBuffer(warnSize, seq)
+	}
+	return b
+}
+
+// Records returns with the records in the buffers, the oldest first
+func (b *LevelRingBuffer) Records() []json.RawMessage {
+	entries := b.low.entries()
+	if b.high != nil {
+		entries = append(entries, b.high.entries()...)
+		slices.SortFunc(entries, func(a, b ringEntry) int { return cmp.Compare(a.seq, b.seq) })
+	}
+	return recordsOf(entries)
+}
+
+// Dump writes the records in the buffers to w as JSON lines, the oldest first
+func (b *LevelRingBuffer) Dump(w io.Writer) error {
+	return dumpRecords(w, b.Records())
+}
+
+func recordsOf(entries []ringEntry) []json.RawMessage {
+	records := make([]json.RawMessage, len(entries))
+	for i, entry := range entries {
+		records[i] = entry.record
+	}
+	return records
+}
+
+func dumpRecords(w io.Writer, records []json.RawMessage) error {
+	for _, record := range records {
+		if _, err := fmt.Fprintf(w, "%s\n", record); err != nil {
+			return fmt.Errorf("failed to dump the recent log records. %w", err)
+		}
+	}
+	return nil
+}
+
+func countTrailingNewlines(p []byte) int {
+	n := 0
+	for n < len(p) && p[len(p)-1-n] == '\n' {
+		n++
+	}
+	return n
+}
+
+// ringBufferHandler is a slog.Handler middleware, that also keeps the records in a ring buffer,
+// including the ones below the level of the wrapped handler down to the level of the buffer.
+type ringBufferHandler struct {
+	next slog.Handler
+	ring slog.Handler
+	// warnRing keeps the WARN or higher level records if it is not nil, see LevelRingBuffer
+	warnRing slog.Handler
+}
+
+// NewRingBufferHandler wraps the handler to also write the records at or above the level into the buffer as JSON.
+// The records in the buffer are redacted and correlated with the traces the same way as the ones of the default logger.
+func NewRingBufferHandler(next slog.Handler, buffer *RingBuffer, level slog.Leveler) slog.Handler {
+	return &ringBufferHandler{next: next, ring: newRingHandler(buffer, level)}
+}
+
+// NewLevelRingBufferHandler wraps the handler to also write the records at or above the level into the level ring buffer as JSON,
+// the same way as NewRingBufferHandler
+func NewLevelRingBufferHandler(next slog.Handler, buffer *LevelRingBuffer, level slog.Leveler) slog.Handler {
+	h := &ringBufferHandler{next: next, ring: newRingHandler(buffer.low, level)}
+	if buffer.high != nil {
+		h.warnRing = newRingHandler(buffer.high, level)
+	}
+	return h
+}
+
+func newRingHandler(buffer *RingBuffer, level slog.Leveler) slog.Handler {
+	ring := slog.NewJSONHandler(buffer, &slog.HandlerOptions{Level: level, ReplaceAttr: replaceLevelName})
+	return NewRedactHandler(NewTraceHandler(ring))
+}
+
+// ringFor returns with the ring handler of the level
+func (h *ringBufferHandler) ringFor(l slog.Level) slog.Handler {
+	if h.warnRing != nil && l >= slog.LevelWarn {
+		return h.warnRing
+	}
+	return h.ring
+}
+
+func (h *ringBufferHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	return h.ringFor(l).Enabled(ctx, l) || h.next.Enabled(ctx, l)
+}
+
+func (h *ringBufferHandler) Handle(ctx context.Context, record slog.Record) error {
+	var errs []error
+	if ring := h.ringFor(record.Level); ring.Enabled(ctx, record.Level) {
+		errs = append(errs, ring.Handle(ctx, record.Clone()))
+	}
+	if h.next.Enabled(ctx, record.Level) {
+		errs = append(errs, h.next.Handle(ctx, record))
+	}
+	return errors.Join(errs...)
+}
+
+func (h *ringBufferHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	child := &ringBufferHandler{next: h.next.WithAttrs(attrs), ring: h.ring.WithAttrs(attrs)}
+	if h.warnRing != nil {
+		child.warnRing = h.warnRing.WithAttrs(attrs)
+	}
+	return child
+}
+
+func (h *ringBufferHandler) WithGroup(name string) slog.Handler {
+	child := &ringBufferHandler{next: h.next.WithGroup(name), ring: h.ring.WithGroup(name)}
+	if h.warnRing != nil {
+		child.warnRing = h.warnRing.WithGroup(name)
+	}
+	return child
+}
+
+// SetupRecentLogs makes the default logger keep its last size records at or above the level in a ring buffer,
+// regardless of the level of the default logger, e.g. to inspect the recent debug records while the level is info.
+// The last warnSize WARN or higher level records are kept separately, so they are not evicted by the lower level ones,
+// see LevelRingBuffer. The buffer is disabled if size is 0. The buffer is kept if SetupDefault is called again.
+func SetupRecentLogs(size int, warnSize int, level slog.Level) {
+	if size <= 0 {
+		recentLogs.Store(nil)
+	} else {
+		recentLogs.Store(&recentLogsBuffer{buffer: NewLevelRingBuffer(size, warnSize), level: level})
+	}
+	if handler := outputsHandler.Load(); handler != nil {
+		setDefaultHandler(*handler)
+	}
+}
+
+// RecentLogs returns with the records in the ring buffer of the default logger, the oldest first,
+// or false if the buffer is disabled
+func RecentLogs() ([]json.RawMessage, bool) {
+	recent := recentLogs.Load()
+	if recent == nil {
+		return nil, false
+	}
+	return recent.buffer.Records(), true
+}
+
+// DumpRecentLogs writes the records in the ring buffer of the default logger to stderr, if the buffer is enabled
+func DumpRecentLogs() {
+	if recent := recentLogs.Load(); recent != nil {
+		_ = recent.buffer.Dump(os.Stderr)
+	}
+}
+
+// DumpRecentLogsOnPanic dumps the recent records of the default logger to stderr on panic, then re-panics.
+// It must be deferred directly, e.g. `defer log.DumpRecentLogsOnPanic()` at the beginning of a goroutine.
+// It only recovers the panics of its own goroutine, so every goroutine, that should dump the records on panic, must defer it.
+func DumpRecentLogsOnPanic() {
+	if r := recover(); r != nil {
+		DumpRecentLogs()
+		panic(r)
+	}
+}
@@ -0,0 +1,97 @@
+package log_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tombenke/go-12f-common/v2/log"
+)
+
+func TestRingBuffer(t *testing.T) {
+	buffer := log.NewRingBuffer(3)
+	assert.Empty(t, buffer.Records())
+	for _, record := range []string{`{"i":1}`, `{"i":2}`, `{"i":3}`, `{"i":4}`} {
+		_, err := buffer.Write([]byte(record + "\n"))
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []json.RawMessage{json.RawMessage(`{"i":2}`), json.RawMessage(`{"i":3}`), json.RawMessage(`{"i":4}`)}, buffer.Records())
+
+	out := bytes.Buffer{}
+	require.NoError(t, buffer.Dump(&out))
+	assert.Equal(t, "{\"i\":2}\n{\"i\":3}\n{\"i\":4}\n", out.String())
+}
+
+func TestRingBufferHandler(t *testing.T) {
+	out := bytes.Buffer{}
+	buffer := log.NewRingBuffer(10)
+	logger := slog.New(log.NewRingBufferHandler(
+		slog.NewTextHandler(&out, &slog.HandlerOptions{Level: slog.LevelInfo}),
+		buffer,
+		slog.LevelDebug,
+	)).With("component", "Worker").WithGroup("req")
+
+	logger.Debug("debug message", "password", "pass")
+	logger.Info("info message", "id", 1)
+	logger.Log(context.Background(), log.LevelTrace, "trace message")
+
+	// The debug records are kept in the buffer, but not written to the output
+	assert.NotContains(t, out.String(), "debug message")
+	assert.Contains(t, out.String(), "info message")
+
+	records := buffer.Records()
+	require.Len(t, records, 2)
+	record := map[string]any{}
+	require.NoError(t, json.Unmarshal(records[0], &record))
+	assert.Equal(t, "debug message", record["msg"])
+	assert.Equal(t, "Worker", record["component"])
+	assert.Equal(t, map[string]any{"password": log.RedactedValue}, record["req"])
+	require.NoError(t, json.Unmarshal(records[1], &record))
+	assert.Equal(t, "info message", record["msg"])
+}
+
+func TestLevelRingBufferHandler(t *testing.T) {
+	messages := func(warnSize int) []string {
+		buffer := log.NewLevelRingBuffer(2, warnSize)
+		logger := slog.New(log.NewLevelRingBufferHandler(slog.NewTextHandler(io.Discard, nil), buffer, slog.LevelDebug))
+		logger.Error("crash")
+		for _, msg := range []string{"first", "second", "third"} {
+			logger.Debug(msg)
+		}
+		messages := []string{}
+		for _, record := range buffer.Records() {
+			parsed := map[string]any{}
+			require.NoError(t, json.Unmarshal(record, &parsed))
+			messages = append(messages, parsed["msg"].(string))
+		}
+		return messages
+	}
+
+	// The burst of debug records does not evict the error, unless they share the buffer
+	assert.Equal(t, []string{"crash", "second", "third"}, messages(1))
+	assert.Equal(t, []string{"second", "third"}, messages(0))
+}
+
+func TestSetupRecentLogs(t *testing.T) {
+	require.NoError(t, log.SetupDefault("info", "text"))
+	defer log.SetupRecentLogs(0, 0, slog.LevelDebug)
+	_, ok := log.RecentLogs()
+	assert.False(t, ok)
+
+	log.SetupRecentLogs(2, 0, slog.LevelDebug)
+	// The buffer is kept if the default logger is set up again
+	require.NoError(t, log.SetupDefault("warn", "json"))
+	for _, msg := range []string{"first", "second", "third"} {
+		log.DebugContext(context.Background(), msg)
+	}
+	records, ok := log.RecentLogs()
+	require.True(t, ok)
+	require.Len(t, records, 2)
+	assert.Contains(t, string(records[0]), `"msg":"second"`)
+	assert.Contains(t, string(records[1]), `"msg":"third"`)
+}
@@ -0,0 +1,254 @@
+package log
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// backupTimeFormat is the UTC timestamp in the names of the rotated files, e.g. app-20240131T235959.000.log
+	backupTimeFormat = "20060102T150405.000"
+	compressSuffix   = ".gz"
+)
+
+// RotationConfig holds the rotation parameters of a log file. The zero value disables the rotation.
+type RotationConfig struct {
+	// MaxSize is the size in bytes, above which the file is rotated. No size based rotation if 0.
+	MaxSize int64
+	// Interval is the age of the file, after which it is rotated. No time based rotation if 0.
+	Interval time.Duration
+	// MaxAge is the age of the rotated files, after which they are removed. No removal by age if 0.
+	MaxAge time.Duration
+	// MaxBackups is the number of the rotated files to keep. All of them are kept if 0.
+	MaxBackups int
+	// Compress the rotated files with gzip
+	Compress bool
+}
+
+// RotatingFile is an io.Writer that appends to a log file, and rotates it by size and age.
+// The rotated files are renamed to <name>-<timestamp><ext>, then compressed and cleaned up in the background.
+type RotatingFile struct {
+	mu       sync.Mutex
+	path     string
+	config   RotationConfig
+	file     *os.File
+	size     int64
+	openedAt time.Time
+
+	// wg tracks the compression and clean up of the rotated files, that cleanUpMu serializes
+	wg        sync.WaitGroup
+	cleanUpMu sync.Mutex
+}
+
+// OpenRotatingFile opens the log file for appending, and creates it and its directory if they do not exist
+func OpenRotatingFile(path string, config RotationConfig) (*RotatingFile, error) {
+	f := &RotatingFile{path: path, config: config}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Path returns with the path of the log file
+func (f *RotatingFile) Path() string {
+	return f.path
+}
+
+// Write appends p to the log file, after rotating it if p would exceed MaxSize or the file is older than Interval
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		return 0, os.ErrClosed
+	}
+	if f.shouldRotate(int64(len(p))) {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err //nolint:wrapcheck // io.Writer errors are returned as is
+}
+
+// Rotate rotates the log file regardless of its size and age
+func (f *RotatingFile) Rotate() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		return os.ErrClosed
+	}
+	return f.rotate()
+}
+
+// Reopen closes and reopens the log file, e.g. after it has been moved by an external logrotate
+func (f *RotatingFile) Reopen() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		return os.ErrClosed
+	}
+	if err := f.file.Close(); err != nil {
+		return fmt.Errorf("failed to close log file. %w", err)
+	}
+	f.file = nil
+	return f.open()
+}
+
+// Close closes the log file, and waits for the compression and clean up of the rotated files
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.wg.Wait()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	if err != nil {
+		return fmt.Errorf("failed to close log file. %w", err)
+	}
+	return nil
+}
+
+func (f *RotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create log directory. %w", err)
+	}
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file. %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return errors.Join(fmt.Errorf("failed to stat log file. %w", err), file.Close())
+	}
+	f.file = file
+	f.size = info.Size()
+	f.openedAt = time.Now()
+	return nil
+}
+
+func (f *RotatingFile) shouldRotate(size int64) bool {
+	if f.config.MaxSize > 0 && f.size > 0 && f.size+size > f.config.MaxSize {
+		return true
+	}
+	return f.config.Interval > 0 && time.Since(f.openedAt) >= f.config.Interval
+}
+
+func (f *RotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return fmt.Errorf("failed to close log file. %w", err)
+	}
+	f.file = nil
+	if err := os.Rename(f.path, f.backupPath(time.Now().UTC())); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to rename log file. %w", err)
+	}
+	if err := f.open(); err != nil {
+		return err
+	}
+
+	f.wg.Add(1)
+	go func() {
+		defer f.wg.Done()
+		if err := f.cleanUp(); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to clean up rotated log files of %s. %s\n", f.path, err)
+		}
+	}()
+	return nil
+}
+
+// backupPath returns with the path of the file rotated at t
+func (f *RotatingFile) backupPath(t time.Time) string {
+	ext := filepath.Ext(f.path)
+	return strings.TrimSuffix(f.path, ext) + "-" + t.Format(backupTimeFormat) + ext
+}
+
+// backup is a rotated log file
+type backup struct {
+	path       string
+	rotatedAt  time.Time
+	compressed bool
+}
+
+// backups returns with the rotated files, the most recent first
+func (f *RotatingFile) backups() ([]backup, error) {
+	dir := filepath.Dir(f.path)
+	ext := filepath.Ext(f.path)
+	prefix := strings.TrimSuffix(filepath.Base(f.path), ext) + "-"
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read log directory. %w", err)
+	}
+
+	backups := []backup{}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		compressed := strings.HasSuffix(name, compressSuffix)
+		timestamp, found := strings.CutSuffix(strings.TrimSuffix(strings.TrimPrefix(name, prefix), compressSuffix), ext)
+		if !found {
+			continue
+		}
+		rotatedAt, err := time.Parse(backupTimeFormat, timestamp)
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backup{path: filepath.Join(dir, name), rotatedAt: rotatedAt, compressed: compressed})
+	}
+	slices.SortFunc(backups, func(a, b backup) int { return b.rotatedAt.Compare(a.rotatedAt) })
+	return backups, nil
+}
+
+// cleanUp removes the rotated files above MaxBackups or older than MaxAge, and compresses the rest if Compress is set
+func (f *RotatingFile) cleanUp() error {
+	f.cleanUpMu.Lock()
+	defer f.cleanUpMu.Unlock()
+	backups, err := f.backups()
+	if err != nil {
+		return err
+	}
+	var errs error
+	for i, b := range backups {
+		switch {
+		case f.config.MaxBackups > 0 && i >= f.config.MaxBackups,
+			f.config.MaxAge > 0 && time.Since(b.rotatedAt) > f.config.MaxAge:
+			errs = errors.Join(errs, os.Remove(b.path))
+		case f.config.Compress && !b.compressed:
+			errs = errors.Join(errs, compressFile(b.path))
+		}
+	}
+	return errs
+}
+
+// compressFile compresses the file with gzip into path.gz, then removes the original one
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open rotated log file. %w", err)
+	}
+	defer src.Close() //nolint:errcheck // read only
+
+	dst, err := os.OpenFile(path+compressSuffix, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to create compressed log file. %w", err)
+	}
+	gz := gzip.NewWriter(dst)
+	if _, err := io.Copy(gz, src); err != nil {
+		return errors.Join(fmt.Errorf("failed to compress rotated log file. %w", err), gz.Close(), dst.Close(), os.Remove(path+compressSuffix))
+	}
+	if err := errors.Join(gz.Close(), dst.Close()); err != nil {
+		return fmt.Errorf("failed to compress rotated log file. %w", err)
+	}
+	return os.Remove(path) //nolint:wrapcheck // the path is in the error
+}
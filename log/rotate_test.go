@@ -0,0 +1,110 @@
+package log_test
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tombenke/go-12f-common/v2/log"
+)
+
+func TestRotatingFileBySize(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "logs", "app.log")
+	file, err := log.OpenRotatingFile(path, log.RotationConfig{MaxSize: 10, MaxBackups: 2})
+	require.NoError(t, err)
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		_, err := file.Write([]byte(line))
+		require.NoError(t, err)
+		// The timestamps of the rotated files differ in milliseconds
+		time.Sleep(2 * time.Millisecond)
+	}
+	require.NoError(t, file.Close())
+
+	current, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "fourth\n", string(current))
+
+	// Only the two most recent rotated files are kept
+	backups, err := filepath.Glob(filepath.Join(dir, "logs", "app-*.log"))
+	require.NoError(t, err)
+	require.Len(t, backups, 2)
+	contents := []string{}
+	for _, backup := range backups {
+		content, err := os.ReadFile(backup)
+		require.NoError(t, err)
+		contents = append(contents, string(content))
+	}
+	assert.Equal(t, []string{"second\n", "third\n"}, contents)
+}
+
+func TestRotatingFileByInterval(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	file, err := log.OpenRotatingFile(path, log.RotationConfig{Interval: 20 * time.Millisecond, Compress: true})
+	require.NoError(t, err)
+
+	_, err = file.Write([]byte("old\n"))
+	require.NoError(t, err)
+	time.Sleep(30 * time.Millisecond)
+	_, err = file.Write([]byte("new\n"))
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	backups, err := filepath.Glob(strings.TrimSuffix(path, ".log") + "-*.log.gz")
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+	compressed, err := os.Open(backups[0])
+	require.NoError(t, err)
+	defer compressed.Close()
+	reader, err := gzip.NewReader(compressed)
+	require.NoError(t, err)
+	content, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, "old\n", string(content))
+}
+
+func TestRotatingFileMaxAge(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	expired := filepath.Join(dir, "app-"+time.Now().Add(-48*time.Hour).UTC().Format("20060102T150405.000")+".log")
+	require.NoError(t, os.WriteFile(expired, []byte("expired\n"), 0o600))
+
+	file, err := log.OpenRotatingFile(path, log.RotationConfig{MaxAge: 24 * time.Hour})
+	require.NoError(t, err)
+	require.NoError(t, file.Rotate())
+	require.NoError(t, file.Close())
+
+	assert.NoFileExists(t, expired)
+	backups, err := filepath.Glob(filepath.Join(dir, "app-*.log"))
+	require.NoError(t, err)
+	assert.Len(t, backups, 1)
+}
+
+func TestRotatingFileReopen(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	file, err := log.OpenRotatingFile(path, log.RotationConfig{})
+	require.NoError(t, err)
+	_, err = file.Write([]byte("before\n"))
+	require.NoError(t, err)
+
+	// An external logrotate moves the file
+	require.NoError(t, os.Rename(path, path+".1"))
+	require.NoError(t, file.Reopen())
+	_, err = file.Write([]byte("after\n"))
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "after\n", string(content))
+	_, err = file.Write([]byte("closed\n"))
+	assert.ErrorIs(t, err, os.ErrClosed)
+}
@@ -0,0 +1,178 @@
+package log
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+const (
+	// MetricDroppedRecords counts the log records dropped by sampling
+	MetricDroppedRecords = "log.records.dropped"
+	// MsgDroppedRecords is the message of the summary of the dropped records
+	MsgDroppedRecords = "Log records dropped by sampling"
+)
+
+// SamplingConfig holds the sampling parameters of the log records with the same level and message.
+// Within each interval the First records pass, then every Thereafter-th of the rest.
+// The error and higher level records always pass.
+type SamplingConfig struct {
+	// Interval of counting the records. The sampling is disabled if 0.
+	Interval time.Duration
+	// First is the number of the records that pass within an interval
+	First int
+	// Thereafter passes every Thereafter-th record after the First ones. The rest is dropped if 0.
+	Thereafter int
+}
+
+// defaultSampler samples the records of the default logger, see SetSampling
+var defaultSampler = newSampler()
+
+// SetSampling sets the sampling of the records of the default logger. The sampling is disabled if the interval is 0.
+func SetSampling(config SamplingConfig) {
+	defaultSampler.setConfig(config)
+}
+
+// sampler counts the records of the current interval, and the dropped ones since the last summary
+type sampler struct {
+	config atomic.Pointer[SamplingConfig]
+
+	mu          sync.Mutex
+	windowStart time.Time
+	counts      map[samplingKey]int
+	dropped     map[string]int64
+	// summaryTimer logs the summary of the dropped records at the end of the interval, it is nil if nothing has been dropped
+	summaryTimer *time.Timer
+
+	droppedCounter metric.Int64Counter
+}
+
+type samplingKey struct {
+	level   slog.Level
+	message string
+}
+
+func newSampler() *sampler {
+	s := &sampler{counts: map[samplingKey]int{}, dropped: map[string]int64{}}
+	s.config.Store(&SamplingConfig{})
+	// The global meter provider delegates to the one set up by the oti package later
+	counter, err := otel.Meter(otelScopeName).Int64Counter(
+		MetricDroppedRecords,
+		metric.WithDescription("The number of the log records dropped by sampling"),
+	)
+	if err != nil {
+		counter = noop.Int64Counter{}
+	}
+	s.droppedCounter = counter
+	return s
+}
+
+func (s *sampler) setConfig(config SamplingConfig) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.config.Store(&config)
+	s.windowStart = time.Time{}
+	clear(s.counts)
+}
+
+// sample tells whether the record passes. The first dropped record of an interval schedules
+// the summary of the dropped records, that is logged by the output handler at the end of the interval.
+func (s *sampler) sample(record slog.Record, output slog.Handler) bool {
+	config := s.config.Load()
+	if config.Interval <= 0 || record.Level >= slog.LevelError {
+		return true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	if now.Sub(s.windowStart) >= config.Interval {
+		s.windowStart = now
+		clear(s.counts)
+	}
+
+	key := samplingKey{level: record.Level, message: record.Message}
+	s.counts[key]++
+	n := s.counts[key]
+	if n <= config.First || (config.Thereafter > 0 && (n-config.First)%config.Thereafter == 0) {
+		return true
+	}
+	s.dropped[record.Message]++
+	if s.summaryTimer == nil {
+		s.summaryTimer = time.AfterFunc(s.windowStart.Add(config.Interval).Sub(now), func() { s.logSummary(output) })
+	}
+	return false
+}
+
+// logSummary logs the number of the dropped records per message since the last summary.
+// The summary is logged without the attributes and groups of the logger, and the context of any record.
+func (s *sampler) logSummary(output slog.Handler) {
+	s.mu.Lock()
+	summary := s.dropped
+	s.dropped = map[string]int64{}
+	s.summaryTimer = nil
+	s.mu.Unlock()
+	if len(summary) == 0 {
+		return
+	}
+
+	var total int64
+	messages := make([]slog.Attr, 0, len(summary))
+	for message, dropped := range summary {
+		total += dropped
+		messages = append(messages, slog.Int64(message, dropped))
+	}
+	ctx := context.Background()
+	record := slog.NewRecord(time.Now(), slog.LevelWarn, MsgDroppedRecords, 0)
+	record.AddAttrs(slog.Int64("dropped", total), slog.Attr{Key: "messages", Value: slog.GroupValue(messages...)})
+	if output.Enabled(ctx, slog.LevelWarn) {
+		_ = output.Handle(ctx, record) // There is no caller to return the error to
+	}
+}
+
+// samplingHandler is a slog.Handler middleware, that drops the records over the sampling limits
+type samplingHandler struct {
+	next    slog.Handler
+	root    slog.Handler
+	sampler *sampler
+}
+
+// NewSamplingHandler wraps the handler to sample the records with the same level and message, see SamplingConfig.
+// The number of the dropped records is counted by the log.records.dropped metric,
+// and logged in a summary record at the end of the interval.
+func NewSamplingHandler(next slog.Handler, config SamplingConfig) slog.Handler {
+	s := newSampler()
+	s.setConfig(config)
+	return newSamplingHandler(next, s)
+}
+
+func newSamplingHandler(next slog.Handler, s *sampler) slog.Handler {
+	return &samplingHandler{next: next, root: next, sampler: s}
+}
+
+func (h *samplingHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	return h.next.Enabled(ctx, l)
+}
+
+func (h *samplingHandler) Handle(ctx context.Context, record slog.Record) error {
+	if !h.sampler.sample(record, h.root) {
+		h.sampler.droppedCounter.Add(ctx, 1, metric.WithAttributes(attribute.String("level", LevelName(record.Level))))
+		return nil
+	}
+	return h.next.Handle(ctx, record)
+}
+
+func (h *samplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &samplingHandler{next: h.next.WithAttrs(attrs), root: h.root, sampler: h.sampler}
+}
+
+func (h *samplingHandler) WithGroup(name string) slog.Handler {
+	return &samplingHandler{next: h.next.WithGroup(name), root: h.root, sampler: h.sampler}
+}
@@ -0,0 +1,94 @@
+package log_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tombenke/go-12f-common/v2/log"
+	"github.com/tombenke/go-12f-common/v2/log/logtest"
+	"go.opentelemetry.io/otel"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSamplingHandler(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+
+	buf := bytes.Buffer{}
+	logger := slog.New(log.NewSamplingHandler(
+		slog.NewTextHandler(&buf, nil),
+		log.SamplingConfig{Interval: 100 * time.Millisecond, First: 2, Thereafter: 3},
+	)).With("component", "Worker")
+
+	for i := range 10 {
+		logger.Info("START", "i", i)
+		logger.Error("failed", "i", i)
+	}
+	logger.Info("END")
+
+	// The first 2, then every 3rd of the rest pass: 0, 1, 4, 7
+	assert.Equal(t, 4, strings.Count(buf.String(), "msg=START"))
+	assert.Contains(t, buf.String(), "msg=START component=Worker i=7")
+	assert.NotContains(t, buf.String(), "msg=START component=Worker i=8")
+	// The errors always pass
+	assert.Equal(t, 10, strings.Count(buf.String(), "msg=failed"))
+	assert.Equal(t, 1, strings.Count(buf.String(), "msg=END"))
+
+	data := metricdata.ResourceMetrics{}
+	require.NoError(t, reader.Collect(context.Background(), &data))
+	require.Len(t, data.ScopeMetrics, 1)
+	require.Len(t, data.ScopeMetrics[0].Metrics, 1)
+	assert.Equal(t, log.MetricDroppedRecords, data.ScopeMetrics[0].Metrics[0].Name)
+	sum := data.ScopeMetrics[0].Metrics[0].Data.(metricdata.Sum[int64])
+	assert.Equal(t, int64(6), sum.DataPoints[0].Value)
+
+	// The summary is logged at the end of the interval, without the attributes of the logger
+	assert.NotContains(t, buf.String(), log.MsgDroppedRecords)
+}
+
+func TestSamplingSummary(t *testing.T) {
+	recorder := logtest.New(t)
+	logger := slog.New(log.NewSamplingHandler(
+		log.NewTraceHandler(recorder.Handler()),
+		log.SamplingConfig{Interval: 100 * time.Millisecond, First: 1},
+	)).With("component", "Worker")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{1},
+		SpanID:  trace.SpanID{1},
+	}))
+
+	// The summary is logged at the end of the interval, even if no record follows the dropped ones,
+	// without the attributes of the logger and the trace of the records
+	for range 3 {
+		logger.InfoContext(ctx, "START")
+	}
+	require.Eventually(t, func() bool { return recorder.Count(logtest.Message(log.MsgDroppedRecords)) == 1 }, time.Second, 10*time.Millisecond)
+	summary := logtest.RequireLogged(t, recorder, logtest.Message(log.MsgDroppedRecords), logtest.Level(slog.LevelWarn), logtest.Attr("dropped", 2), logtest.Attr("messages.START", 2))
+	_, hasTrace := summary.Attr(log.FieldTraceID)
+	assert.False(t, hasTrace)
+	_, hasComponent := summary.Attr("component")
+	assert.False(t, hasComponent)
+
+	// No summary without dropped records
+	recorder.Reset()
+	logger.InfoContext(ctx, "START")
+	time.Sleep(200 * time.Millisecond)
+	logtest.AssertNotLogged(t, recorder, logtest.Message(log.MsgDroppedRecords))
+}
+
+func TestSamplingDisabled(t *testing.T) {
+	buf := bytes.Buffer{}
+	logger := slog.New(log.NewSamplingHandler(slog.NewTextHandler(&buf, nil), log.SamplingConfig{First: 1}))
+	for range 5 {
+		logger.Info("START")
+	}
+	assert.Equal(t, 5, strings.Count(buf.String(), "msg=START"))
+}
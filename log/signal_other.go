@@ -0,0 +1,15 @@
+//go:build !unix
+
+package log
+
+import "context"
+
+// NotifyLevelSignals is a no-op on the platforms without SIGUSR1 and SIGUSR2
+func NotifyLevelSignals(ctx context.Context) (stop func()) {
+	return func() {}
+}
+
+// NotifyReopenSignal is a no-op on the platforms without SIGHUP
+func NotifyReopenSignal(ctx context.Context) (stop func()) {
+	return func() {}
+}
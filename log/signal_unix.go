@@ -0,0 +1,67 @@
+//go:build unix
+
+package log
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// NotifyLevelSignals makes the default logger more verbose on SIGUSR1, and less verbose on SIGUSR2,
+// until the returned stop function is called.
+func NotifyLevelSignals(ctx context.Context) (stop func()) {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGUSR1, syscall.SIGUSR2)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case s := <-sigs:
+				steps := 1
+				if s == syscall.SIGUSR1 {
+					steps = -1
+				}
+				from := GetLevel()
+				InfoContext(ctx, "Changing log level", "signal", s, "from", LevelName(from), "to", LevelName(StepLevel(steps)))
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(sigs)
+		close(done)
+	}
+}
+
+// NotifyReopenSignal reopens the log files of the default logger on SIGHUP, e.g. after an external logrotate moved them,
+// until the returned stop function is called.
+func NotifyReopenSignal(ctx context.Context) (stop func()) {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGHUP)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case s := <-sigs:
+				if err := ReopenOutputs(); err != nil {
+					ErrorContext(ctx, "Failed to reopen log files", "signal", s, ErrorAttr(err))
+				} else {
+					InfoContext(ctx, "Reopened log files", "signal", s)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(sigs)
+		close(done)
+	}
+}
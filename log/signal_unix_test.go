@@ -0,0 +1,53 @@
+//go:build unix
+
+package log_test
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tombenke/go-12f-common/v2/log"
+)
+
+func TestNotifyLevelSignals(t *testing.T) {
+	require.NoError(t, log.SetupDefault("info", "text"))
+	stop := log.NotifyLevelSignals(t.Context())
+	defer stop()
+
+	require.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGUSR1))
+	require.Eventually(t, func() bool {
+		return log.GetLevel() == slog.LevelDebug
+	}, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGUSR2))
+	require.Eventually(t, func() bool {
+		return log.GetLevel() == slog.LevelInfo
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestNotifyReopenSignal(t *testing.T) {
+	defer func() { require.NoError(t, log.SetupDefault("info", "text")) }()
+	path := filepath.Join(t.TempDir(), "app.log")
+	outputs, err := log.ParseOutputs([]string{"file:" + path}, "text", log.RotationConfig{})
+	require.NoError(t, err)
+	require.NoError(t, log.SetupDefaultOutputs("info", outputs))
+	stop := log.NotifyReopenSignal(t.Context())
+	defer stop()
+
+	require.NoError(t, os.Rename(path, path+".1"))
+	require.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGHUP))
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(path)
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+	log.InfoContext(context.Background(), "after reopen")
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Contains(t, string(content), "after reopen")
+}
@@ -2,10 +2,12 @@ package log
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"os"
-	"strings"
+	"sync/atomic"
 
+	"go.opentelemetry.io/contrib/bridges/otelslog"
 	"go.opentelemetry.io/otel/attribute"
 )
 
@@ -18,32 +20,42 @@ const (
 	loggerKey loggerKeyType = iota
 
 	FieldError = "error"
+
+	// otelScopeName is the instrumentation scope of the log records bridged to OTEL
+	otelScopeName = "github.com/tombenke/go-12f-common/v2/log"
 )
 
-// Setup the default logger with the given level and format
-func SetupDefault(logLevel string, logFormat string) {
-	leveler := slog.LevelInfo
-	switch strings.ToLower(logLevel) {
-	case "panic", "fatal", "error":
-		leveler = slog.LevelError
-	case "info":
-		leveler = slog.LevelInfo
-	case "warning":
-		leveler = slog.LevelWarn
-	case "debug", "trace":
-		leveler = slog.LevelDebug
-	}
-	slogHandlerOptions := &slog.HandlerOptions{
-		Level: leveler,
-	}
-	var slogHandler slog.Handler
-	switch strings.ToLower(logFormat) {
-	case "json":
-		slogHandler = slog.NewJSONHandler(os.Stderr, slogHandlerOptions)
-	case "text":
-		slogHandler = slog.NewTextHandler(os.Stderr, slogHandlerOptions)
+var (
+	// fatalHook is called by FatalContext before exiting
+	fatalHook atomic.Pointer[func(ctx context.Context)]
+	// exit terminates the process, it is replaced by the tests
+	exit = os.Exit
+	// outputsHandler is the handler of the outputs of the default logger, that the middlewares wrap
+	outputsHandler atomic.Pointer[slog.Handler]
+
+	// ErrUnknownLevel is returned when parsing an unknown log level name
+	ErrUnknownLevel = errors.New("unknown log level")
+	// ErrUnknownFormat is returned by SetupDefault for an unknown log format
+	ErrUnknownFormat = errors.New("unknown log format")
+)
+
+// Setup the default logger with the given level and format, that writes to stderr.
+// It returns with error and leaves the default logger unchanged if the level or the format is unknown.
+func SetupDefault(logLevel string, logFormat string) error {
+	return SetupDefaultOutputs(logLevel, []Output{{Format: logFormat, Writer: os.Stderr}})
+}
+
+// setDefaultHandler sets the default logger with the middlewares around the handler of the outputs
+func setDefaultHandler(handler slog.Handler) {
+	outputsHandler.Store(&handler)
+	// The records are also bridged to the OTEL logs pipeline, that is enabled by the oti package if a logs exporter is set,
+	// see SetOtelLoggerProvider
+	otelHandler := otelslog.NewHandler(otelScopeName, otelslog.WithLoggerProvider(swappableLoggerProvider{}))
+	defaultHandler := NewComponentLevelHandler(newSamplingHandler(NewRedactHandler(NewTeeHandler(NewTraceHandler(handler), otelHandler)), defaultSampler))
+	if recent := recentLogs.Load(); recent != nil {
+		defaultHandler = NewLevelRingBufferHandler(defaultHandler, recent.buffer, recent.level)
 	}
-	slog.SetDefault(slog.New(slogHandler))
+	slog.SetDefault(slog.New(defaultHandler))
 }
 
 // Adds fields to the logger in the context or the default one, then returns the context with the child logger
@@ -157,3 +169,33 @@ func ErrorContext(ctx context.Context, msg string, args ...any) {
 	logger := GetFromContextOrDefault(ctx)
 	logger.ErrorContext(ctx, msg, args...)
 }
+
+// Logs with the logger in the context or the default one at trace level
+func TraceContext(ctx context.Context, msg string, args ...any) {
+	logger := GetFromContextOrDefault(ctx)
+	logger.Log(ctx, LevelTrace, msg, args...)
+}
+
+// Logs with the logger in the context or the default one at fatal level,
+// then calls the hook set by SetFatalHook, e.g. to flush the telemetry, dumps the recent records, and exits the process with status 1
+func FatalContext(ctx context.Context, msg string, args ...any) {
+	logger := GetFromContextOrDefault(ctx)
+	logger.Log(ctx, LevelFatal, msg, args...)
+	if hook := fatalHook.Load(); hook != nil && *hook != nil {
+		(*hook)(ctx)
+	}
+	DumpRecentLogs()
+	exit(1)
+}
+
+// Logs with the logger in the context or the default one at panic level, then panics with the message
+func PanicContext(ctx context.Context, msg string, args ...any) {
+	logger := GetFromContextOrDefault(ctx)
+	logger.Log(ctx, LevelPanic, msg, args...)
+	panic(msg)
+}
+
+// SetFatalHook sets the function that FatalContext calls before exiting the process, e.g. to flush the telemetry
+func SetFatalHook(hook func(ctx context.Context)) {
+	fatalHook.Store(&hook)
+}
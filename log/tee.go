@@ -0,0 +1,51 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+)
+
+// teeHandler is a slog.Handler, that passes the records to several handlers.
+// The first handler decides which levels are enabled, the others only get the records they are also enabled for.
+type teeHandler struct {
+	primary slog.Handler
+	others  []slog.Handler
+}
+
+// NewTeeHandler creates a handler that passes the records to the primary and the other handlers.
+// Only the primary handler decides whether a level is enabled, so the level of the primary applies to all of them,
+// but the other handlers may further filter the records by their own Enabled().
+func NewTeeHandler(primary slog.Handler, others ...slog.Handler) slog.Handler {
+	return &teeHandler{primary: primary, others: others}
+}
+
+func (h *teeHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	return h.primary.Enabled(ctx, l)
+}
+
+func (h *teeHandler) Handle(ctx context.Context, record slog.Record) error {
+	errs := []error{h.primary.Handle(ctx, record)}
+	for _, other := range h.others {
+		if other.Enabled(ctx, record.Level) {
+			errs = append(errs, other.Handle(ctx, record))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (h *teeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return h.with(func(next slog.Handler) slog.Handler { return next.WithAttrs(attrs) })
+}
+
+func (h *teeHandler) WithGroup(name string) slog.Handler {
+	return h.with(func(next slog.Handler) slog.Handler { return next.WithGroup(name) })
+}
+
+func (h *teeHandler) with(derive func(slog.Handler) slog.Handler) slog.Handler {
+	child := &teeHandler{primary: derive(h.primary), others: make([]slog.Handler, len(h.others))}
+	for i, other := range h.others {
+		child.others[i] = derive(other)
+	}
+	return child
+}
@@ -0,0 +1,77 @@
+package log_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tombenke/go-12f-common/v2/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTeeHandler(t *testing.T) {
+	primary := bytes.Buffer{}
+	other := bytes.Buffer{}
+	logger := slog.New(log.NewTeeHandler(
+		slog.NewTextHandler(&primary, &slog.HandlerOptions{Level: slog.LevelDebug}),
+		slog.NewTextHandler(&other, &slog.HandlerOptions{Level: slog.LevelInfo}),
+	)).With("app", "test")
+
+	logger.Info("info message")
+	logger.Debug("debug message")
+	logger.Log(context.Background(), log.LevelTrace, "trace message")
+
+	assert.Contains(t, primary.String(), "info message")
+	assert.Contains(t, primary.String(), "debug message")
+	assert.NotContains(t, primary.String(), "trace message")
+	assert.Contains(t, other.String(), `msg="info message" app=test`)
+	assert.NotContains(t, other.String(), "debug message")
+}
+
+// recordingProcessor keeps the OTEL log records emitted
+type recordingProcessor struct {
+	mu      sync.Mutex
+	records []sdklog.Record
+}
+
+func (p *recordingProcessor) OnEmit(ctx context.Context, record *sdklog.Record) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.records = append(p.records, record.Clone())
+	return nil
+}
+
+func (p *recordingProcessor) Shutdown(ctx context.Context) error   { return nil }
+func (p *recordingProcessor) ForceFlush(ctx context.Context) error { return nil }
+
+func TestOtelLogsBridge(t *testing.T) {
+	require.NoError(t, log.SetupDefault("info", "json"))
+	ctx, logger := log.With(context.Background(), log.FieldComponent, "Worker")
+
+	// The loggers created before the OTEL logs pipeline is set up get bridged too
+	processor := &recordingProcessor{}
+	provider := sdklog.NewLoggerProvider(sdklog.WithProcessor(processor))
+	log.SetOtelLoggerProvider(provider)
+
+	spanContext := trace.NewSpanContext(trace.SpanContextConfig{TraceID: trace.TraceID{0x01}, SpanID: trace.SpanID{0x02}})
+	logger.InfoContext(trace.ContextWithSpanContext(ctx, spanContext), "bridged message")
+	logger.DebugContext(ctx, "filtered message")
+
+	// Nothing is bridged after the provider is cleared
+	log.SetOtelLoggerProvider(nil)
+	require.NoError(t, provider.Shutdown(context.Background()))
+	logger.InfoContext(ctx, "not bridged message")
+
+	processor.mu.Lock()
+	defer processor.mu.Unlock()
+	require.Len(t, processor.records, 1)
+	record := processor.records[0]
+	assert.Equal(t, "bridged message", record.Body().AsString())
+	assert.Equal(t, spanContext.TraceID(), record.TraceID())
+	assert.Equal(t, spanContext.SpanID(), record.SpanID())
+}
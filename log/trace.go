@@ -0,0 +1,50 @@
+package log
+
+import (
+	"context"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// The attributes of the trace correlation, that are added to the log records by the trace handler
+const (
+	FieldTraceID    = "trace_id"
+	FieldSpanID     = "span_id"
+	FieldTraceFlags = "trace_flags"
+)
+
+// traceHandler is a slog.Handler middleware, that correlates the log records with the active span of their context
+type traceHandler struct {
+	next slog.Handler
+}
+
+// NewTraceHandler wraps the handler to add the trace_id, span_id and trace_flags attributes of the active span
+// in the context of the record, so any log.InfoContext(ctx, ...) call within a span is correlated with the trace.
+func NewTraceHandler(next slog.Handler) slog.Handler {
+	return &traceHandler{next: next}
+}
+
+func (h *traceHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	return h.next.Enabled(ctx, l)
+}
+
+func (h *traceHandler) Handle(ctx context.Context, record slog.Record) error {
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		record = record.Clone()
+		record.AddAttrs(
+			slog.String(FieldTraceID, spanContext.TraceID().String()),
+			slog.String(FieldSpanID, spanContext.SpanID().String()),
+			slog.String(FieldTraceFlags, spanContext.TraceFlags().String()),
+		)
+	}
+	return h.next.Handle(ctx, record)
+}
+
+func (h *traceHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &traceHandler{next: h.next.WithAttrs(attrs)}
+}
+
+func (h *traceHandler) WithGroup(name string) slog.Handler {
+	return &traceHandler{next: h.next.WithGroup(name)}
+}
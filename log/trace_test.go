@@ -0,0 +1,39 @@
+package log_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tombenke/go-12f-common/v2/log"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTraceHandler(t *testing.T) {
+	buf := bytes.Buffer{}
+	logger := slog.New(log.NewTraceHandler(slog.NewJSONHandler(&buf, nil))).With("app", "test")
+
+	spanContext := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x01, 0x02, 0x03},
+		SpanID:     trace.SpanID{0x04, 0x05},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), spanContext)
+
+	logger.InfoContext(ctx, "within span")
+	record := map[string]any{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, spanContext.TraceID().String(), record[log.FieldTraceID])
+	assert.Equal(t, spanContext.SpanID().String(), record[log.FieldSpanID])
+	assert.Equal(t, "01", record[log.FieldTraceFlags])
+	assert.Equal(t, "test", record["app"])
+
+	// No correlation without an active span
+	buf.Reset()
+	logger.InfoContext(context.Background(), "without span")
+	assert.NotContains(t, buf.String(), log.FieldTraceID)
+}
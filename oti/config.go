@@ -15,14 +15,94 @@ const (
 	OTEL_TRACES_EXPORTER_DEFAULT  = "none"
 	OTEL_TRACES_EXPORTER_HELP     = "Selects the exporter to use for tracing: otlp | console | none"
 
+	OTEL_TRACES_SAMPLER_ARG_NAME = "otel-traces-sampler"
+	OTEL_TRACES_SAMPLER_DEFAULT  = SamplerParentBasedAlwaysOn
+	OTEL_TRACES_SAMPLER_HELP     = "The sampler of the traces: always_on | always_off | traceidratio | parentbased_always_on | parentbased_always_off | parentbased_traceidratio"
+
+	OTEL_TRACES_SAMPLER_ARG_ARG_NAME = "otel-traces-sampler-arg"
+	OTEL_TRACES_SAMPLER_ARG_DEFAULT  = 1.0
+	OTEL_TRACES_SAMPLER_ARG_HELP     = "The ratio of the sampled traces of the traceidratio and parentbased_traceidratio samplers, between 0 and 1"
+
+	OTEL_TRACES_SAMPLER_RULES_ARG_NAME = "otel-traces-sampler-rules"
+	OTEL_TRACES_SAMPLER_RULES_DEFAULT  = ""
+	OTEL_TRACES_SAMPLER_RULES_HELP     = "Comma separated rules to sample or drop the spans by their name or route before the sampler, e.g. drop:/healthz,sample:/orders/*"
+
+	OTEL_TRACES_SAMPLER_ERRORS_ARG_NAME = "otel-traces-sampler-errors"
+	OTEL_TRACES_SAMPLER_ERRORS_DEFAULT  = false
+	OTEL_TRACES_SAMPLER_ERRORS_HELP     = "Exports the spans ending with error, even if the sampler dropped them. The spans dropped by the sampler are recorded in memory, the ones dropped by the rules are not"
+
+	OTEL_BSP_SCHEDULE_DELAY_ARG_NAME = "otel-bsp-schedule-delay"
+	OTEL_BSP_SCHEDULE_DELAY_DEFAULT  = 5000
+	OTEL_BSP_SCHEDULE_DELAY_HELP     = "The max delay between two exports of the spans in milliseconds"
+
+	OTEL_BSP_EXPORT_TIMEOUT_ARG_NAME = "otel-bsp-export-timeout"
+	OTEL_BSP_EXPORT_TIMEOUT_DEFAULT  = 30000
+	OTEL_BSP_EXPORT_TIMEOUT_HELP     = "The timeout of the exports of the spans in milliseconds"
+
+	OTEL_BSP_MAX_QUEUE_SIZE_ARG_NAME = "otel-bsp-max-queue-size"
+	OTEL_BSP_MAX_QUEUE_SIZE_DEFAULT  = 2048
+	OTEL_BSP_MAX_QUEUE_SIZE_HELP     = "The max number of the spans waiting for export, the new spans are dropped if the queue is full"
+
+	OTEL_BSP_MAX_EXPORT_BATCH_SIZE_ARG_NAME = "otel-bsp-max-export-batch-size"
+	OTEL_BSP_MAX_EXPORT_BATCH_SIZE_DEFAULT  = 512
+	OTEL_BSP_MAX_EXPORT_BATCH_SIZE_HELP     = "The max number of the spans exported at once, must not be greater than the max queue size"
+
+	OTEL_TRACES_SYNC_EXPORT_ARG_NAME = "otel-traces-sync-export"
+	OTEL_TRACES_SYNC_EXPORT_DEFAULT  = false
+	OTEL_TRACES_SYNC_EXPORT_HELP     = "Exports the spans synchronously when they end instead of batching them, e.g. in the tests"
+
 	OTEL_METRICS_EXPORTER_ARG_NAME = "otel-metrics-exporter"
 	OTEL_METRICS_EXPORTER_DEFAULT  = "none"
 	OTEL_METRICS_EXPORTER_HELP     = "Selects the exporter to use for metrics: otlp | prometheus | console | none"
 
+	OTEL_METRIC_EXPORT_INTERVAL_ARG_NAME = "otel-metric-export-interval"
+	OTEL_METRIC_EXPORT_INTERVAL_DEFAULT  = 60000
+	OTEL_METRIC_EXPORT_INTERVAL_HELP     = "The interval between the exports of the metrics by the otlp and console exporters in milliseconds"
+
+	OTEL_METRIC_EXPORT_TIMEOUT_ARG_NAME = "otel-metric-export-timeout"
+	OTEL_METRIC_EXPORT_TIMEOUT_DEFAULT  = 30000
+	OTEL_METRIC_EXPORT_TIMEOUT_HELP     = "The timeout of the exports of the metrics by the otlp and console exporters in milliseconds"
+
+	OTEL_METRIC_VIEWS_ARG_NAME = "otel-metric-views"
+	OTEL_METRIC_VIEWS_DEFAULT  = ""
+	OTEL_METRIC_VIEWS_HELP     = "Semicolon separated views of the metrics, the first matching one is applied, e.g. http.server.duration:buckets=0.01|0.1|1|10;rpc.*:histogram=exponential,drop=rpc.method"
+
+	OTEL_LOGS_EXPORTER_ARG_NAME = "otel-logs-exporter"
+	OTEL_LOGS_EXPORTER_DEFAULT  = "none"
+	OTEL_LOGS_EXPORTER_HELP     = "Selects the exporter to use for logs: otlp | console | none"
+
 	OTEL_EXPORTER_PROMETHEUS_PORT_ARG_NAME = "otel-exporter-prometheus-port"
 	OTEL_EXPORTER_PROMETHEUS_PORT_DEFAULT  = 9464
 	OTEL_EXPORTER_PROMETHEUS_PORT_HELP     = "the port used by the Prometheus exporter"
 
+	OTEL_EXPORTER_OTLP_ENDPOINT_ARG_NAME = "otel-exporter-otlp-endpoint"
+	OTEL_EXPORTER_OTLP_ENDPOINT_DEFAULT  = ""
+	OTEL_EXPORTER_OTLP_ENDPOINT_HELP     = "The endpoint of the OTLP exporters, e.g. http://collector:4318 or collector:4317. The default of the protocol is used if empty"
+
+	OTEL_EXPORTER_OTLP_PROTOCOL_ARG_NAME = "otel-exporter-otlp-protocol"
+	OTEL_EXPORTER_OTLP_PROTOCOL_DEFAULT  = OtlpProtocolGRPC
+	OTEL_EXPORTER_OTLP_PROTOCOL_HELP     = "The protocol of the OTLP exporters: grpc | http/protobuf"
+
+	OTEL_EXPORTER_OTLP_INSECURE_ARG_NAME = "otel-exporter-otlp-insecure"
+	OTEL_EXPORTER_OTLP_INSECURE_DEFAULT  = false
+	OTEL_EXPORTER_OTLP_INSECURE_HELP     = "Disables the TLS of the OTLP exporters"
+
+	OTEL_EXPORTER_OTLP_HEADERS_ARG_NAME = "otel-exporter-otlp-headers"
+	OTEL_EXPORTER_OTLP_HEADERS_DEFAULT  = ""
+	OTEL_EXPORTER_OTLP_HEADERS_HELP     = "The headers sent by the OTLP exporters, e.g. api-key=secret,tenant=acme. The values are URL-encoded"
+
+	OTEL_EXPORTER_OTLP_COMPRESSION_ARG_NAME = "otel-exporter-otlp-compression"
+	OTEL_EXPORTER_OTLP_COMPRESSION_DEFAULT  = OtlpCompressionNone
+	OTEL_EXPORTER_OTLP_COMPRESSION_HELP     = "The compression of the OTLP exporters: gzip | none"
+
+	OTEL_EXPORTER_OTLP_TIMEOUT_ARG_NAME = "otel-exporter-otlp-timeout"
+	OTEL_EXPORTER_OTLP_TIMEOUT_DEFAULT  = 0
+	OTEL_EXPORTER_OTLP_TIMEOUT_HELP     = "The timeout of the exports of the OTLP exporters in milliseconds. The default of the exporters, 10000, is used if 0"
+
+	OTEL_EXPORTER_OTLP_CERTIFICATE_ARG_NAME = "otel-exporter-otlp-certificate"
+	OTEL_EXPORTER_OTLP_CERTIFICATE_DEFAULT  = ""
+	OTEL_EXPORTER_OTLP_CERTIFICATE_HELP     = "The PEM file of the CA certificates to verify the server certificate of the OTLP endpoint. The system CAs are used if empty"
+
 	FieldTraceID        = attribute.Key("trace.id")
 	FieldSpanID         = attribute.Key("span.id")
 	FieldSpanKind       = attribute.Key("span.kind")
@@ -42,6 +122,7 @@ const (
 	FieldComponent      = attribute.Key("component")
 	FieldNetLayer       = attribute.Key("network.layer")
 	FieldExporter       = attribute.Key("exporter")
+	FieldLogExporter    = attribute.Key("log.exporter")
 	FieldStatusCode     = semconv.HTTPResponseStatusCodeKey
 	FieldReqLen         = semconv.HTTPRequestBodySizeKey
 	FieldRespLen        = semconv.HTTPResponseBodySizeKey
@@ -54,7 +135,8 @@ const (
 
 	FieldMessageType = "messageType"
 
-	FieldError = attribute.Key("error")
+	FieldError      = attribute.Key("error")
+	FieldErrorChain = attribute.Key("error.chain")
 
 	EventOK = "ok"
 
@@ -82,24 +164,104 @@ type Config struct {
 	// Possible values are: "otlp": OTLP, "jaeger": Jaeger, "zipkin": Zipkin, "console": Standard Output, "none": No automatically configured exporter for tracing
 	OtelTracesExporter string `mapstructure:"otel-traces-exporter"`
 
+	// OtelTracesSampler is the sampler of the traces, e.g. parentbased_traceidratio, see NewSampler
+	OtelTracesSampler string `mapstructure:"otel-traces-sampler"`
+
+	// OtelTracesSamplerArg is the ratio of the sampled traces of the ratio based samplers
+	OtelTracesSamplerArg float64 `mapstructure:"otel-traces-sampler-arg"`
+
+	// OtelTracesSamplerRules are the rules applied before the sampler, see ParseSamplingRules
+	OtelTracesSamplerRules string `mapstructure:"otel-traces-sampler-rules"`
+
+	// OtelTracesSamplerErrors exports the spans ending with error, even if they are not sampled, see NewErrorSampler
+	OtelTracesSamplerErrors bool `mapstructure:"otel-traces-sampler-errors"`
+
+	// The parameters of the batch span processor, see BatchConfig. The durations are in milliseconds,
+	// like the OTEL_BSP_* environment variables of the specification.
+	OtelBspScheduleDelay      int `mapstructure:"otel-bsp-schedule-delay"`
+	OtelBspExportTimeout      int `mapstructure:"otel-bsp-export-timeout"`
+	OtelBspMaxQueueSize       int `mapstructure:"otel-bsp-max-queue-size"`
+	OtelBspMaxExportBatchSize int `mapstructure:"otel-bsp-max-export-batch-size"`
+
+	// OtelTracesSyncExport exports the spans synchronously, e.g. in the tests
+	OtelTracesSyncExport bool `mapstructure:"otel-traces-sync-export"`
+
 	// OtelMetricsExporter specifies which exporter is used for metrics
 	// Possible values are: "otlp": OTLP, "prometheus": Prometheus, "console": Standard Output, "none": No automatically configured exporter for metrics
 	OtelMetricsExporter string `mapstructure:"otel-metrics-exporter"`
 
+	// OtelMetricExportInterval and OtelMetricExportTimeout are the interval and timeout of the exports of the periodic readers
+	// in milliseconds, like the OTEL_METRIC_EXPORT_* environment variables of the specification
+	OtelMetricExportInterval int `mapstructure:"otel-metric-export-interval"`
+	OtelMetricExportTimeout  int `mapstructure:"otel-metric-export-timeout"`
+
+	// OtelMetricViews are the views of all the meter providers, see ParseViews
+	OtelMetricViews string `mapstructure:"otel-metric-views"`
+
+	// OtelLogsExporter specifies which exporter is used for logs, besides writing them to the standard error
+	// Possible values are: "otlp": OTLP, "console": Standard Output, "none": No automatically configured exporter for logs
+	OtelLogsExporter string `mapstructure:"otel-logs-exporter"`
+
 	// OtelExporterPrometheusPort specifies the port that the prometheus exporter uses to provide the metrics
 	OtelExporterPrometheusPort int `mapstructure:"otel-exporter-prometheus-port"`
+
+	// The parameters of the OTLP exporters of the traces, metrics and logs.
+	// Their environment variables are the ones of the OpenTelemetry specification, e.g. OTEL_EXPORTER_OTLP_ENDPOINT.
+	OtelExporterOtlpEndpoint    string `mapstructure:"otel-exporter-otlp-endpoint"`
+	OtelExporterOtlpProtocol    string `mapstructure:"otel-exporter-otlp-protocol"`
+	OtelExporterOtlpInsecure    bool   `mapstructure:"otel-exporter-otlp-insecure"`
+	OtelExporterOtlpHeaders     string `mapstructure:"otel-exporter-otlp-headers" log:"redact"`
+	OtelExporterOtlpCompression string `mapstructure:"otel-exporter-otlp-compression"`
+	// OtelExporterOtlpTimeout is in milliseconds, like the OTEL_EXPORTER_OTLP_TIMEOUT of the specification
+	OtelExporterOtlpTimeout     int    `mapstructure:"otel-exporter-otlp-timeout"`
+	OtelExporterOtlpCertificate string `mapstructure:"otel-exporter-otlp-certificate"`
 }
 
 func (cfg *Config) GetConfigFlagSet(flagSet *pflag.FlagSet) {
 	flagSet.String(OTEL_TRACES_EXPORTER_ARG_NAME, OTEL_TRACES_EXPORTER_DEFAULT, OTEL_TRACES_EXPORTER_HELP)
+	flagSet.String(OTEL_TRACES_SAMPLER_ARG_NAME, OTEL_TRACES_SAMPLER_DEFAULT, OTEL_TRACES_SAMPLER_HELP)
+	flagSet.Float64(OTEL_TRACES_SAMPLER_ARG_ARG_NAME, OTEL_TRACES_SAMPLER_ARG_DEFAULT, OTEL_TRACES_SAMPLER_ARG_HELP)
+	flagSet.String(OTEL_TRACES_SAMPLER_RULES_ARG_NAME, OTEL_TRACES_SAMPLER_RULES_DEFAULT, OTEL_TRACES_SAMPLER_RULES_HELP)
+	flagSet.Bool(OTEL_TRACES_SAMPLER_ERRORS_ARG_NAME, OTEL_TRACES_SAMPLER_ERRORS_DEFAULT, OTEL_TRACES_SAMPLER_ERRORS_HELP)
+	flagSet.Int(OTEL_BSP_SCHEDULE_DELAY_ARG_NAME, OTEL_BSP_SCHEDULE_DELAY_DEFAULT, OTEL_BSP_SCHEDULE_DELAY_HELP)
+	flagSet.Int(OTEL_BSP_EXPORT_TIMEOUT_ARG_NAME, OTEL_BSP_EXPORT_TIMEOUT_DEFAULT, OTEL_BSP_EXPORT_TIMEOUT_HELP)
+	flagSet.Int(OTEL_BSP_MAX_QUEUE_SIZE_ARG_NAME, OTEL_BSP_MAX_QUEUE_SIZE_DEFAULT, OTEL_BSP_MAX_QUEUE_SIZE_HELP)
+	flagSet.Int(OTEL_BSP_MAX_EXPORT_BATCH_SIZE_ARG_NAME, OTEL_BSP_MAX_EXPORT_BATCH_SIZE_DEFAULT, OTEL_BSP_MAX_EXPORT_BATCH_SIZE_HELP)
+	flagSet.Bool(OTEL_TRACES_SYNC_EXPORT_ARG_NAME, OTEL_TRACES_SYNC_EXPORT_DEFAULT, OTEL_TRACES_SYNC_EXPORT_HELP)
 	flagSet.String(OTEL_METRICS_EXPORTER_ARG_NAME, OTEL_METRICS_EXPORTER_DEFAULT, OTEL_METRICS_EXPORTER_HELP)
+	flagSet.Int(OTEL_METRIC_EXPORT_INTERVAL_ARG_NAME, OTEL_METRIC_EXPORT_INTERVAL_DEFAULT, OTEL_METRIC_EXPORT_INTERVAL_HELP)
+	flagSet.Int(OTEL_METRIC_EXPORT_TIMEOUT_ARG_NAME, OTEL_METRIC_EXPORT_TIMEOUT_DEFAULT, OTEL_METRIC_EXPORT_TIMEOUT_HELP)
+	flagSet.String(OTEL_METRIC_VIEWS_ARG_NAME, OTEL_METRIC_VIEWS_DEFAULT, OTEL_METRIC_VIEWS_HELP)
+	flagSet.String(OTEL_LOGS_EXPORTER_ARG_NAME, OTEL_LOGS_EXPORTER_DEFAULT, OTEL_LOGS_EXPORTER_HELP)
 	flagSet.Int(OTEL_EXPORTER_PROMETHEUS_PORT_ARG_NAME, OTEL_EXPORTER_PROMETHEUS_PORT_DEFAULT, OTEL_EXPORTER_PROMETHEUS_PORT_HELP)
+	flagSet.String(OTEL_EXPORTER_OTLP_ENDPOINT_ARG_NAME, OTEL_EXPORTER_OTLP_ENDPOINT_DEFAULT, OTEL_EXPORTER_OTLP_ENDPOINT_HELP)
+	flagSet.String(OTEL_EXPORTER_OTLP_PROTOCOL_ARG_NAME, OTEL_EXPORTER_OTLP_PROTOCOL_DEFAULT, OTEL_EXPORTER_OTLP_PROTOCOL_HELP)
+	flagSet.Bool(OTEL_EXPORTER_OTLP_INSECURE_ARG_NAME, OTEL_EXPORTER_OTLP_INSECURE_DEFAULT, OTEL_EXPORTER_OTLP_INSECURE_HELP)
+	flagSet.String(OTEL_EXPORTER_OTLP_HEADERS_ARG_NAME, OTEL_EXPORTER_OTLP_HEADERS_DEFAULT, OTEL_EXPORTER_OTLP_HEADERS_HELP)
+	flagSet.String(OTEL_EXPORTER_OTLP_COMPRESSION_ARG_NAME, OTEL_EXPORTER_OTLP_COMPRESSION_DEFAULT, OTEL_EXPORTER_OTLP_COMPRESSION_HELP)
+	flagSet.Int(OTEL_EXPORTER_OTLP_TIMEOUT_ARG_NAME, OTEL_EXPORTER_OTLP_TIMEOUT_DEFAULT, OTEL_EXPORTER_OTLP_TIMEOUT_HELP)
+	flagSet.String(OTEL_EXPORTER_OTLP_CERTIFICATE_ARG_NAME, OTEL_EXPORTER_OTLP_CERTIFICATE_DEFAULT, OTEL_EXPORTER_OTLP_CERTIFICATE_HELP)
 }
 
 func (cfg *Config) LoadConfig(flagSet *pflag.FlagSet) error {
 	if err := config.LoadConfigWithDefaultViper(flagSet, cfg); err != nil {
 		return fmt.Errorf("failed to load otel config. %w", err)
 	}
+	if _, err := cfg.otlpExporterConfig(); err != nil {
+		return fmt.Errorf("failed to load otel config. %w", err)
+	}
+	if _, err := cfg.traceSampler(); err != nil {
+		return fmt.Errorf("failed to load otel config. %w", err)
+	}
+	if _, err := cfg.batchConfig(); err != nil {
+		return fmt.Errorf("failed to load otel config. %w", err)
+	}
+	if _, err := cfg.meterProviderOptions(); err != nil {
+		return fmt.Errorf("failed to load otel config. %w", err)
+	}
+	if _, err := cfg.periodicReaderOptions(); err != nil {
+		return fmt.Errorf("failed to load otel config. %w", err)
+	}
 	return nil
 }
 
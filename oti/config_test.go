@@ -15,9 +15,21 @@ func TestOtelConfigWithDefaults(t *testing.T) {
 	config.GetConfigFlagSet(fs)
 	require.NoError(t, config.LoadConfig(fs))
 	assert.Equal(t, Config{
-		OtelTracesExporter:         OTEL_TRACES_EXPORTER_DEFAULT,
-		OtelMetricsExporter:        OTEL_METRICS_EXPORTER_DEFAULT,
-		OtelExporterPrometheusPort: OTEL_EXPORTER_PROMETHEUS_PORT_DEFAULT,
+		OtelTracesExporter:          OTEL_TRACES_EXPORTER_DEFAULT,
+		OtelTracesSampler:           OTEL_TRACES_SAMPLER_DEFAULT,
+		OtelTracesSamplerArg:        OTEL_TRACES_SAMPLER_ARG_DEFAULT,
+		OtelBspScheduleDelay:        OTEL_BSP_SCHEDULE_DELAY_DEFAULT,
+		OtelBspExportTimeout:        OTEL_BSP_EXPORT_TIMEOUT_DEFAULT,
+		OtelBspMaxQueueSize:         OTEL_BSP_MAX_QUEUE_SIZE_DEFAULT,
+		OtelBspMaxExportBatchSize:   OTEL_BSP_MAX_EXPORT_BATCH_SIZE_DEFAULT,
+		OtelMetricsExporter:         OTEL_METRICS_EXPORTER_DEFAULT,
+		OtelMetricExportInterval:    OTEL_METRIC_EXPORT_INTERVAL_DEFAULT,
+		OtelMetricExportTimeout:     OTEL_METRIC_EXPORT_TIMEOUT_DEFAULT,
+		OtelLogsExporter:            OTEL_LOGS_EXPORTER_DEFAULT,
+		OtelExporterPrometheusPort:  OTEL_EXPORTER_PROMETHEUS_PORT_DEFAULT,
+		OtelExporterOtlpProtocol:    OTEL_EXPORTER_OTLP_PROTOCOL_DEFAULT,
+		OtelExporterOtlpCompression: OTEL_EXPORTER_OTLP_COMPRESSION_DEFAULT,
+		OtelExporterOtlpTimeout:     OTEL_EXPORTER_OTLP_TIMEOUT_DEFAULT,
 	}, config)
 }
 
@@ -28,17 +40,22 @@ func Test_Config_GetConfigFlagSet(t *testing.T) {
 	const EXPECTED_OTEL_METRICS_EXPORTER_FROM_ENV_VAR = "env_prometheus"
 	const EXPECTED_OTEL_METRICS_EXPORTER_FROM_CLI_ARG = "cli_prometheus"
 
+	const EXPECTED_OTEL_LOGS_EXPORTER_FROM_ENV_VAR = "env_console"
+	const EXPECTED_OTEL_LOGS_EXPORTER_FROM_CLI_ARG = "cli_console"
+
 	const EXPECTED_OTEL_EXPORTER_PROMETHEUS_PORT_FROM_ENV_VAR = 1234
 	const EXPECTED_OTEL_EXPORTER_PROMETHEUS_PORT_FROM_CLI_ARG = 5678
 
 	envVars := map[string]string{
 		"OTEL_TRACES_EXPORTER":          EXPECTED_OTEL_TRACES_EXPORTER_FROM_ENV_VAR,
 		"OTEL_METRICS_EXPORTER":         EXPECTED_OTEL_METRICS_EXPORTER_FROM_ENV_VAR,
+		"OTEL_LOGS_EXPORTER":            EXPECTED_OTEL_LOGS_EXPORTER_FROM_ENV_VAR,
 		"OTEL_EXPORTER_PROMETHEUS_PORT": fmt.Sprintf("%v", EXPECTED_OTEL_EXPORTER_PROMETHEUS_PORT_FROM_ENV_VAR),
 	}
 	cliArgs := []string{
 		fmt.Sprintf("--%v=%v", OTEL_TRACES_EXPORTER_ARG_NAME, EXPECTED_OTEL_TRACES_EXPORTER_FROM_CLI_ARG),
 		fmt.Sprintf("--%v=%v", OTEL_METRICS_EXPORTER_ARG_NAME, EXPECTED_OTEL_METRICS_EXPORTER_FROM_CLI_ARG),
+		fmt.Sprintf("--%v=%v", OTEL_LOGS_EXPORTER_ARG_NAME, EXPECTED_OTEL_LOGS_EXPORTER_FROM_CLI_ARG),
 		fmt.Sprintf("--%v=%v", OTEL_EXPORTER_PROMETHEUS_PORT_ARG_NAME, EXPECTED_OTEL_EXPORTER_PROMETHEUS_PORT_FROM_CLI_ARG),
 	}
 	testCases := map[string]struct {
@@ -48,32 +65,80 @@ func Test_Config_GetConfigFlagSet(t *testing.T) {
 	}{
 		"default values": {
 			expectedConfig: Config{
-				OtelTracesExporter:         OTEL_TRACES_EXPORTER_DEFAULT,
-				OtelMetricsExporter:        OTEL_METRICS_EXPORTER_DEFAULT,
-				OtelExporterPrometheusPort: OTEL_EXPORTER_PROMETHEUS_PORT_DEFAULT,
+				OtelTracesExporter:          OTEL_TRACES_EXPORTER_DEFAULT,
+				OtelTracesSampler:           OTEL_TRACES_SAMPLER_DEFAULT,
+				OtelTracesSamplerArg:        OTEL_TRACES_SAMPLER_ARG_DEFAULT,
+				OtelBspScheduleDelay:        OTEL_BSP_SCHEDULE_DELAY_DEFAULT,
+				OtelBspExportTimeout:        OTEL_BSP_EXPORT_TIMEOUT_DEFAULT,
+				OtelBspMaxQueueSize:         OTEL_BSP_MAX_QUEUE_SIZE_DEFAULT,
+				OtelBspMaxExportBatchSize:   OTEL_BSP_MAX_EXPORT_BATCH_SIZE_DEFAULT,
+				OtelMetricsExporter:         OTEL_METRICS_EXPORTER_DEFAULT,
+				OtelMetricExportInterval:    OTEL_METRIC_EXPORT_INTERVAL_DEFAULT,
+				OtelMetricExportTimeout:     OTEL_METRIC_EXPORT_TIMEOUT_DEFAULT,
+				OtelLogsExporter:            OTEL_LOGS_EXPORTER_DEFAULT,
+				OtelExporterPrometheusPort:  OTEL_EXPORTER_PROMETHEUS_PORT_DEFAULT,
+				OtelExporterOtlpProtocol:    OTEL_EXPORTER_OTLP_PROTOCOL_DEFAULT,
+				OtelExporterOtlpCompression: OTEL_EXPORTER_OTLP_COMPRESSION_DEFAULT,
+				OtelExporterOtlpTimeout:     OTEL_EXPORTER_OTLP_TIMEOUT_DEFAULT,
 			},
 		},
 		"from environment variables": {
 			expectedConfig: Config{
-				OtelTracesExporter:         EXPECTED_OTEL_TRACES_EXPORTER_FROM_ENV_VAR,
-				OtelMetricsExporter:        EXPECTED_OTEL_METRICS_EXPORTER_FROM_ENV_VAR,
-				OtelExporterPrometheusPort: EXPECTED_OTEL_EXPORTER_PROMETHEUS_PORT_FROM_ENV_VAR,
+				OtelTracesExporter:          EXPECTED_OTEL_TRACES_EXPORTER_FROM_ENV_VAR,
+				OtelTracesSampler:           OTEL_TRACES_SAMPLER_DEFAULT,
+				OtelTracesSamplerArg:        OTEL_TRACES_SAMPLER_ARG_DEFAULT,
+				OtelBspScheduleDelay:        OTEL_BSP_SCHEDULE_DELAY_DEFAULT,
+				OtelBspExportTimeout:        OTEL_BSP_EXPORT_TIMEOUT_DEFAULT,
+				OtelBspMaxQueueSize:         OTEL_BSP_MAX_QUEUE_SIZE_DEFAULT,
+				OtelBspMaxExportBatchSize:   OTEL_BSP_MAX_EXPORT_BATCH_SIZE_DEFAULT,
+				OtelMetricsExporter:         EXPECTED_OTEL_METRICS_EXPORTER_FROM_ENV_VAR,
+				OtelMetricExportInterval:    OTEL_METRIC_EXPORT_INTERVAL_DEFAULT,
+				OtelMetricExportTimeout:     OTEL_METRIC_EXPORT_TIMEOUT_DEFAULT,
+				OtelLogsExporter:            EXPECTED_OTEL_LOGS_EXPORTER_FROM_ENV_VAR,
+				OtelExporterPrometheusPort:  EXPECTED_OTEL_EXPORTER_PROMETHEUS_PORT_FROM_ENV_VAR,
+				OtelExporterOtlpProtocol:    OTEL_EXPORTER_OTLP_PROTOCOL_DEFAULT,
+				OtelExporterOtlpCompression: OTEL_EXPORTER_OTLP_COMPRESSION_DEFAULT,
+				OtelExporterOtlpTimeout:     OTEL_EXPORTER_OTLP_TIMEOUT_DEFAULT,
 			},
 			envVars: envVars,
 		},
 		"from cli args": {
 			expectedConfig: Config{
-				OtelTracesExporter:         EXPECTED_OTEL_TRACES_EXPORTER_FROM_CLI_ARG,
-				OtelMetricsExporter:        EXPECTED_OTEL_METRICS_EXPORTER_FROM_CLI_ARG,
-				OtelExporterPrometheusPort: EXPECTED_OTEL_EXPORTER_PROMETHEUS_PORT_FROM_CLI_ARG,
+				OtelTracesExporter:          EXPECTED_OTEL_TRACES_EXPORTER_FROM_CLI_ARG,
+				OtelTracesSampler:           OTEL_TRACES_SAMPLER_DEFAULT,
+				OtelTracesSamplerArg:        OTEL_TRACES_SAMPLER_ARG_DEFAULT,
+				OtelBspScheduleDelay:        OTEL_BSP_SCHEDULE_DELAY_DEFAULT,
+				OtelBspExportTimeout:        OTEL_BSP_EXPORT_TIMEOUT_DEFAULT,
+				OtelBspMaxQueueSize:         OTEL_BSP_MAX_QUEUE_SIZE_DEFAULT,
+				OtelBspMaxExportBatchSize:   OTEL_BSP_MAX_EXPORT_BATCH_SIZE_DEFAULT,
+				OtelMetricsExporter:         EXPECTED_OTEL_METRICS_EXPORTER_FROM_CLI_ARG,
+				OtelMetricExportInterval:    OTEL_METRIC_EXPORT_INTERVAL_DEFAULT,
+				OtelMetricExportTimeout:     OTEL_METRIC_EXPORT_TIMEOUT_DEFAULT,
+				OtelLogsExporter:            EXPECTED_OTEL_LOGS_EXPORTER_FROM_CLI_ARG,
+				OtelExporterPrometheusPort:  EXPECTED_OTEL_EXPORTER_PROMETHEUS_PORT_FROM_CLI_ARG,
+				OtelExporterOtlpProtocol:    OTEL_EXPORTER_OTLP_PROTOCOL_DEFAULT,
+				OtelExporterOtlpCompression: OTEL_EXPORTER_OTLP_COMPRESSION_DEFAULT,
+				OtelExporterOtlpTimeout:     OTEL_EXPORTER_OTLP_TIMEOUT_DEFAULT,
 			},
 			cliArgs: cliArgs,
 		},
 		"prefer cli args over env vars": {
 			expectedConfig: Config{
-				OtelTracesExporter:         EXPECTED_OTEL_TRACES_EXPORTER_FROM_CLI_ARG,
-				OtelMetricsExporter:        EXPECTED_OTEL_METRICS_EXPORTER_FROM_CLI_ARG,
-				OtelExporterPrometheusPort: EXPECTED_OTEL_EXPORTER_PROMETHEUS_PORT_FROM_CLI_ARG,
+				OtelTracesExporter:          EXPECTED_OTEL_TRACES_EXPORTER_FROM_CLI_ARG,
+				OtelTracesSampler:           OTEL_TRACES_SAMPLER_DEFAULT,
+				OtelTracesSamplerArg:        OTEL_TRACES_SAMPLER_ARG_DEFAULT,
+				OtelBspScheduleDelay:        OTEL_BSP_SCHEDULE_DELAY_DEFAULT,
+				OtelBspExportTimeout:        OTEL_BSP_EXPORT_TIMEOUT_DEFAULT,
+				OtelBspMaxQueueSize:         OTEL_BSP_MAX_QUEUE_SIZE_DEFAULT,
+				OtelBspMaxExportBatchSize:   OTEL_BSP_MAX_EXPORT_BATCH_SIZE_DEFAULT,
+				OtelMetricsExporter:         EXPECTED_OTEL_METRICS_EXPORTER_FROM_CLI_ARG,
+				OtelMetricExportInterval:    OTEL_METRIC_EXPORT_INTERVAL_DEFAULT,
+				OtelMetricExportTimeout:     OTEL_METRIC_EXPORT_TIMEOUT_DEFAULT,
+				OtelLogsExporter:            EXPECTED_OTEL_LOGS_EXPORTER_FROM_CLI_ARG,
+				OtelExporterPrometheusPort:  EXPECTED_OTEL_EXPORTER_PROMETHEUS_PORT_FROM_CLI_ARG,
+				OtelExporterOtlpProtocol:    OTEL_EXPORTER_OTLP_PROTOCOL_DEFAULT,
+				OtelExporterOtlpCompression: OTEL_EXPORTER_OTLP_COMPRESSION_DEFAULT,
+				OtelExporterOtlpTimeout:     OTEL_EXPORTER_OTLP_TIMEOUT_DEFAULT,
 			},
 			envVars: envVars,
 			cliArgs: cliArgs,
@@ -2,14 +2,17 @@ package oti
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/gorilla/mux"
+	logger "github.com/tombenke/go-12f-common/v2/log"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -35,7 +38,7 @@ func HttpServerLoggerBaseMiddleware(ctxRoot context.Context) func(next http.Hand
 		fn := func(w http.ResponseWriter, r *http.Request) {
 			var urlFull, urlHost, urlPath string
 			if r.URL != nil {
-				urlFull = r.URL.String()
+				urlFull = logger.RedactURL(r.URL)
 				urlHost = r.URL.Host
 				urlPath = r.URL.Path
 			}
@@ -69,8 +72,6 @@ func HttpServerTracerMiddleware(tr trace.Tracer, instance string, spanLogLevel i
 				Log(ctx, spanLogLevel, MsgSpanIn,
 					FieldSpan, spanValues,
 					FieldSpanErr, spanValuesErr,
-					FieldTraceID, span.SpanContext().TraceID().String(),
-					FieldSpanID, span.SpanContext().SpanID().String(),
 				)
 			} else {
 				span = trace.SpanFromContext(ctx)
@@ -78,12 +79,9 @@ func HttpServerTracerMiddleware(tr trace.Tracer, instance string, spanLogLevel i
 				Log(ctx, spanLogLevel, MsgSpanNew,
 					FieldSpan, spanValues,
 					FieldSpanErr, spanValuesErr,
-					FieldTraceID, span.SpanContext().TraceID().String(),
-					FieldSpanID, span.SpanContext().SpanID().String(),
 				)
 			}
 
-			spanParent := span
 			spanKind := trace.SpanKindServer
 			ctx, span = tr.Start(ctx, "IN HTTP "+r.Method+" "+r.URL.String(),
 				trace.WithAttributes(semconv_legacy.NetAttributesFromHTTPRequest("tcp", r)...),
@@ -91,12 +89,7 @@ func HttpServerTracerMiddleware(tr trace.Tracer, instance string, spanLogLevel i
 				trace.WithAttributes(semconv_legacy.HTTPServerAttributesFromHTTPRequest(instance, routePath, r)...),
 				trace.WithSpanKind(spanKind),
 			)
-			spanLogValues := []interface{}{FieldTraceID, span.SpanContext().TraceID().String()}
-			if spanParent.SpanContext().IsValid() { // Log span.id from client side
-				spanLogValues = append(spanLogValues, FieldSpanID, spanParent.SpanContext().SpanID().String())
-			}
-			spanLogValues = append(spanLogValues, FieldSpanID, span.SpanContext().SpanID().String())
-			ctx = LogWithValues(ctx, spanLogValues...)
+			ctx = LogWithSpanContext(ctx)
 			Log(ctx, spanLogLevel, MsgSpanStart, FieldSpanKind, spanKind.String())
 
 			uk := attribute.Key("username") // from HTTP header
@@ -235,8 +228,16 @@ func NewRecoveryLogger(log *slog.Logger) *RecoveryLogger {
 	return &RecoveryLogger{log: log}
 }
 
+// Println logs the recovered panic value as an error with the stack trace of the panic
 func (rl *RecoveryLogger) Println(v ...any) {
-	rl.log.Warn("PANIC recovered:", "error", fmt.Sprintln(v...))
+	var err error
+	if len(v) == 1 {
+		err, _ = v[0].(error)
+	}
+	if err == nil {
+		err = errors.New(strings.TrimSpace(fmt.Sprintln(v...)))
+	}
+	rl.log.Warn("PANIC recovered:", logger.ErrorAttr(logger.WithStack(err)))
 }
 
 /*
@@ -272,10 +273,9 @@ func NewLogTransport(base http.RoundTripper, beginLevel int, endLevel int) *LogT
 // RoundTrip logs outgoing request and response.
 func (t *LogTransport) RoundTrip(r *http.Request) (*http.Response, error) {
 	ctx := r.Context()
-	span := trace.SpanFromContext(ctx)
 	var urlFull, urlHost, urlPath string
 	if r.URL != nil {
-		urlFull = r.URL.String()
+		urlFull = logger.RedactURL(r.URL)
 		urlHost = r.URL.Host
 		urlPath = r.URL.Path
 	}
@@ -284,9 +284,6 @@ func (t *LogTransport) RoundTrip(r *http.Request) (*http.Response, error) {
 		semconv.URLFullKey, urlFull,
 		semconv.URLDomainKey, urlHost,
 		semconv.URLPathKey, urlPath,
-		// Skip: most probable traceID is already added to the log fields
-		// FieldTraceID, span.SpanContext().TraceID(),
-		FieldSpanID, span.SpanContext().SpanID(),
 	)
 	var res *http.Response
 	var err error
@@ -312,7 +309,7 @@ func (t *LogTransport) RoundTrip(r *http.Request) (*http.Response, error) {
 		FieldDuration, fmt.Sprintf("%.3f", elapsedSec),
 	}
 	if err != nil {
-		args = append(args, FieldError, err)
+		args = append(args, FieldError, logger.ErrorValue(err))
 	}
 	Log(ctx, t.endLevel, MsgOutResp, args...)
 
@@ -0,0 +1,23 @@
+package oti
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecoveryLogger(t *testing.T) {
+	buf := bytes.Buffer{}
+	NewRecoveryLogger(slog.New(slog.NewJSONHandler(&buf, nil))).Println("boom", 42)
+
+	record := map[string]any{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	errorGroup, ok := record["error"].(map[string]any)
+	require.True(t, ok, buf.String())
+	assert.Equal(t, "boom 42", errorGroup["message"])
+	assert.Contains(t, errorGroup["stack"], "TestRecoveryLogger")
+}
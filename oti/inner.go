@@ -5,14 +5,16 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
-	"runtime/debug"
 	"strings"
 	"time"
 
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 	metric_api "go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 	"go.opentelemetry.io/otel/trace"
+
+	logger "github.com/tombenke/go-12f-common/v2/log"
 )
 
 type InternalMiddlewareFn[T any] func(ctx context.Context) (T, error)
@@ -82,22 +84,16 @@ var (
 func Span[T any](tr trace.Tracer, spanKind trace.SpanKind, spanName string) InternalMiddleware[T] {
 	return func(next InternalMiddlewareFn[T]) InternalMiddlewareFn[T] {
 		return func(ctx context.Context) (T, error) {
-			spanParent := trace.SpanFromContext(ctx).SpanContext()
 			ctx, spanChild := tr.Start(ctx, spanName,
 				trace.WithSpanKind(spanKind),
 			)
 			defer spanChild.End()
-
-			logFields := []any{}
-			if !spanParent.IsValid() {
-				logFields = append(logFields, FieldTraceID, spanChild.SpanContext().TraceID().String())
-			}
-			logFields = append(logFields, FieldSpanID, spanChild.SpanContext().SpanID().String())
-			ctx = LogWithValues(ctx, logFields...)
+			ctx = LogWithSpanContext(ctx)
 
 			t, err := next(ctx)
 			if err != nil {
-				spanChild.RecordError(err, trace.WithAttributes(FieldValue.String(fmt.Sprintf("%+v", t))))
+				spanChild.RecordError(err, trace.WithAttributes(
+					append(errorEventAttributes(err), FieldValue.String(fmt.Sprintf("%+v", t)))...))
 				spanChild.SetStatus(codes.Error, err.Error())
 			} else {
 				spanChild.AddEvent(EventOK, trace.WithAttributes(FieldValue.String(fmt.Sprintf("%+v", t))))
@@ -124,6 +120,19 @@ func TryCatch[T any]() InternalMiddleware[T] {
 	}
 }
 
+// errorEventAttributes returns with the attributes of the error events of the spans,
+// that match the error attribute of the log records, see log.ErrorAttr
+func errorEventAttributes(err error) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{semconv.ErrorTypeKey.String(logger.ErrorType(err))}
+	if chain := logger.ErrorChain(err); len(chain) > 0 {
+		attrs = append(attrs, FieldErrorChain.StringSlice(chain))
+	}
+	if stack, ok := logger.ErrorStack(err); ok {
+		attrs = append(attrs, semconv.ExceptionStacktrace(stack))
+	}
+	return attrs
+}
+
 // ErrPanic is an error for captured panic
 var ErrPanic = errors.New("captured panic")
 
@@ -132,7 +141,7 @@ func tryCatch(f func()) func() error {
 	return func() (err error) {
 		defer func() {
 			if panicInfo := recover(); panicInfo != nil {
-				err = fmt.Errorf("%w: %v, %s", ErrPanic, panicInfo, string(debug.Stack()))
+				err = logger.WithStack(fmt.Errorf("%w: %v", ErrPanic, panicInfo))
 
 				return
 			}
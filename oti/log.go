@@ -10,6 +10,7 @@ import (
 	"github.com/go-logr/logr"
 	logger "github.com/tombenke/go-12f-common/v2/log"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var (
@@ -32,6 +33,21 @@ func LogWithValues(ctx context.Context, args ...any) context.Context {
 	return ctx
 }
 
+// LogWithSpanContext adds the trace and span IDs of the active span to the logr logger of the context.
+// The slog-based loggers set up by log.SetupDefault correlate the records with the active span automatically,
+// so the context is returned unchanged for them.
+func LogWithSpanContext(ctx context.Context) context.Context {
+	logR, errR := logr.FromContext(ctx)
+	spanContext := trace.SpanContextFromContext(ctx)
+	if errR != nil || !spanContext.IsValid() {
+		return ctx
+	}
+	return logr.NewContext(ctx, logR.WithValues(
+		logger.FieldTraceID, spanContext.TraceID().String(),
+		logger.FieldSpanID, spanContext.SpanID().String(),
+	))
+}
+
 func CopyLogger(ctxTo context.Context, ctxFrom context.Context) context.Context {
 	logR, errR := logr.FromContext(ctxFrom)
 	if errR == nil { // logr
@@ -105,7 +121,7 @@ func LogError(ctx context.Context, err error, msg string, args ...any) {
 
 	// slog-based go-12f-common
 	_, logS := logger.FromContext(ctx, args...)
-	logS.Log(ctx, slog.LevelError, msg, string(FieldError), err)
+	logS.Log(ctx, slog.LevelError, msg, logger.ErrorAttr(err))
 }
 
 func DotToUsIf[T ~string](s T, dotToUs bool) string {
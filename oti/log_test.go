@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"reflect"
 	"sync"
 	"testing"
@@ -11,6 +12,7 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/tombenke/go-12f-common/v2/log/logtest"
 	"go.opentelemetry.io/otel/attribute"
 )
 
@@ -203,3 +205,15 @@ func TestDotToUsHelpers(t *testing.T) {
 	assert.Equal(t, "keep.me", DotToUsIf("keep.me", false))
 	assert.Equal(t, "keep_me", DotToUsIf("keep.me", true))
 }
+
+// TestLogSlog validates Log and LogError with the slog logger of the context
+func TestLogSlog(t *testing.T) {
+	recorder := logtest.New(t)
+	ctx := LogWithValues(recorder.NewContext(context.Background()), attribute.Key("msg.subject"), "orders")
+
+	Log(ctx, 4, "hello", "k1", "v1")
+	LogError(ctx, errors.New("boom"), "failed")
+
+	logtest.AssertLogged(t, recorder, logtest.Level(slog.LevelWarn), logtest.Message("hello"), logtest.Attr("k1", "v1"), logtest.Attr("msg_subject", "orders"))
+	logtest.AssertLogged(t, recorder, logtest.Level(slog.LevelError), logtest.Message("failed"), logtest.Attr(KeyError, "boom"))
+}
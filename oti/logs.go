@@ -0,0 +1,67 @@
+package oti
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tombenke/go-12f-common/v2/log"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutlog"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+type LogExporterType string
+
+var (
+	LogExporterTypeOTLP    LogExporterType = "otlp"
+	LogExporterTypeConsole LogExporterType = "console"
+	LogExporterTypeNone    LogExporterType = "none"
+)
+
+// Startup Logs. The records of the default logger set up by log.SetupDefault are bridged to its LoggerProvider.
+func (o *Otel) startupLogs(ctx context.Context, res *resource.Resource) error {
+	exporterType := strings.ToLower(o.config.OtelLogsExporter)
+	Log(ctx, 0, "Startup Logs", FieldLogExporter, exporterType)
+
+	var exporter sdklog.Exporter
+	var err error
+	switch LogExporterType(exporterType) {
+	case LogExporterTypeOTLP:
+		otlp, errOtlp := o.config.otlpExporterConfig()
+		if errOtlp != nil {
+			return errOtlp
+		}
+		exporter, err = newOtlpLogExporter(ctx, otlp)
+	case LogExporterTypeConsole:
+		exporter, err = stdoutlog.New()
+	case LogExporterTypeNone, "":
+		return nil
+	default:
+		return fmt.Errorf("%w: wrong logs exporter type: %s", ErrOtelConfig, o.config.OtelLogsExporter)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create logs exporter. %w", err)
+	}
+
+	o.loggerProvider = sdklog.NewLoggerProvider(
+		sdklog.WithResource(res),
+		sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter)),
+	)
+	log.SetOtelLoggerProvider(o.loggerProvider)
+	return nil
+}
+
+// Shutdown Logs, flushing the records that have not been exported yet
+func (o *Otel) shutdownLogs(ctx context.Context) {
+	ctx = LogWithValues(ctx, FieldComponent, "Otel.Logs")
+	Log(ctx, 0, "Shutdown")
+
+	if o.loggerProvider != nil {
+		// Stop bridging the records before shutting down, so they do not go to the closed provider
+		log.SetOtelLoggerProvider(nil)
+		if err := o.loggerProvider.Shutdown(ctx); err != nil {
+			LogError(ctx, err, "failed LoggerProvider shutdown")
+		}
+	}
+}
@@ -8,13 +8,11 @@ import (
 	"sync"
 
 	client_prometheus "github.com/prometheus/client_golang/prometheus"
-	"github.com/tombenke/go-12f-common/v2/must"
 
 	////"time"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
 	"go.opentelemetry.io/otel/exporters/prometheus"
 	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
 	metric_api "go.opentelemetry.io/otel/metric"
@@ -46,20 +44,39 @@ var (
 )
 
 // Initializes an OTLP MeterProvider
-func initOtlpMeterProvider(ctx context.Context, res *resource.Resource) (*sdkmetric.MeterProvider, error) {
+func initOtlpMeterProvider(ctx context.Context, otlp otlpExporterConfig, res *resource.Resource, config Config) (*sdkmetric.MeterProvider, error) {
+	metricExporter, err := newOtlpMetricExporter(ctx, otlp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create metrics exporter: %w", err)
+	}
 
-	metricExporter := must.MustVal(otlpmetricgrpc.New(ctx))
+	return newPeriodicMeterProvider(metricExporter, res, config)
+}
 
-	meterProvider := sdkmetric.NewMeterProvider(
-		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(metricExporter)),
-		sdkmetric.WithResource(res),
-	)
+// newPeriodicMeterProvider creates a MeterProvider, that exports the metrics by the exporter periodically
+func newPeriodicMeterProvider(exporter sdkmetric.Exporter, res *resource.Resource, config Config) (*sdkmetric.MeterProvider, error) {
+	readerOptions, err := config.periodicReaderOptions()
+	if err != nil {
+		return nil, err
+	}
+	options, err := config.meterProviderOptions()
+	if err != nil {
+		return nil, err
+	}
 
-	return meterProvider, nil
+	return sdkmetric.NewMeterProvider(append(options,
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter, readerOptions...)),
+		sdkmetric.WithResource(res),
+	)...), nil
 }
 
 // Initializes a Prometheus MeterProvider
-func initPrometheusMeterProvider(_ context.Context, res *resource.Resource) (*sdkmetric.MeterProvider, error) {
+func initPrometheusMeterProvider(_ context.Context, res *resource.Resource, config Config) (*sdkmetric.MeterProvider, error) {
+	options, err := config.meterProviderOptions()
+	if err != nil {
+		return nil, err
+	}
+
 	// The exporter embeds a default OpenTelemetry Reader and
 	// implements prometheus.Collector, allowing it to be used as
 	// both a Reader and Collector.
@@ -67,16 +84,16 @@ func initPrometheusMeterProvider(_ context.Context, res *resource.Resource) (*sd
 	if err != nil {
 		return nil, fmt.Errorf("failed to create metrics exporter: %w", err)
 	}
-	meterProvider := sdkmetric.NewMeterProvider(
+	meterProvider := sdkmetric.NewMeterProvider(append(options,
 		sdkmetric.WithReader(exporter),
 		sdkmetric.WithResource(res),
-	)
+	)...)
 
 	return meterProvider, nil
 }
 
 // Initializes a Console MeterProvider
-func initConsoleMeterProvider(res *resource.Resource, output ConsoleMeterProviderOut) (*sdkmetric.MeterProvider, error) {
+func initConsoleMeterProvider(res *resource.Resource, output ConsoleMeterProviderOut, config Config) (*sdkmetric.MeterProvider, error) {
 	metricOptions := []stdoutmetric.Option{}
 	switch output {
 	case ConsoleNone:
@@ -90,25 +107,24 @@ func initConsoleMeterProvider(res *resource.Resource, output ConsoleMeterProvide
 		return nil, err
 	}
 
-	meterProvider := sdkmetric.NewMeterProvider(
-		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(metricExporter)),
-		// NOTE: Use the OTEL_METRIC_EXPORT_INTERVAL environment variable to control the interval
-		// sdkmetric.WithInterval(3*time.Second)
-
-		sdkmetric.WithResource(res),
-	)
-
-	return meterProvider, nil
+	return newPeriodicMeterProvider(metricExporter, res, config)
 }
 
 func Int64CounterGetInstrument(name string, options ...metric_api.Int64CounterOption) (metric_api.Int64Counter, error) {
+	initMeter()
 	return regInt64Counter.GetInstrument(name, options...)
 }
 
 func Float64CounterGetInstrument(name string, options ...metric_api.Float64CounterOption) (metric_api.Float64Counter, error) {
+	initMeter()
 	return regFloat64Counter.GetInstrument(name, options...)
 }
 
+func Int64GaugeGetInstrument(name string, options ...metric_api.Int64GaugeOption) (metric_api.Int64Gauge, error) {
+	initMeter()
+	return regInt64Gauge.GetInstrument(name, options...)
+}
+
 // InstrumentReg stores the already registered instruments
 //
 //nolint:structcheck // generics
@@ -144,12 +160,19 @@ var (
 	regInt64Counter *InstrumentReg[metric_api.Int64Counter, metric_api.Int64CounterOption] //nolint:gochecknoglobals // private
 	// regFloat64Counter stores Float64Counters
 	regFloat64Counter *InstrumentReg[metric_api.Float64Counter, metric_api.Float64CounterOption] //nolint:gochecknoglobals // private
+	// regInt64Gauge stores Int64Gauges
+	regInt64Gauge *InstrumentReg[metric_api.Int64Gauge, metric_api.Int64GaugeOption] //nolint:gochecknoglobals // private
 )
 
 // GetMeter returns the default meter.
 // Inits meter and InstrumentRegs (if needed)
 func GetMeter(ctx context.Context) metric_api.Meter {
+	initMeter()
+	return meter
+}
 
+// initMeter inits meter and InstrumentRegs once
+func initMeter() {
 	meterOnce.Do(func() {
 		meter = otel.GetMeterProvider().Meter(buildinfo.ModulePath(GetMeter), metric_api.WithInstrumentationVersion("0.1"))
 
@@ -161,9 +184,11 @@ func GetMeter(ctx context.Context) metric_api.Meter {
 			instruments:   map[string]metric_api.Float64Counter{},
 			newInstrument: meter.Float64Counter,
 		}
+		regInt64Gauge = &InstrumentReg[metric_api.Int64Gauge, metric_api.Int64GaugeOption]{
+			instruments:   map[string]metric_api.Int64Gauge{},
+			newInstrument: meter.Int64Gauge,
+		}
 	})
-
-	return meter
 }
 
 func GetHost(r *http.Request) string {
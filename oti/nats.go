@@ -13,7 +13,6 @@ import (
 	"go.opentelemetry.io/otel/trace"
 
 	"github.com/tombenke/go-12f-common/v2/buildinfo"
-	"github.com/tombenke/go-12f-common/v2/log"
 )
 
 // NatsCarrier adapts nats.Header to satisfy the TextMapCarrier interface.
@@ -129,12 +128,6 @@ func ObsNatsMsgHandler(rootCtx context.Context, fn func(context.Context, *nats.M
 		ctx := context.Background()
 		if msg.Header.Get(TraceparentHeader) != "" {
 			ctx = otel.GetTextMapPropagator().Extract(rootCtx, NatsCarrier(msg.Header))
-			// oti.Span does not set traceID spanID to the context logger, if it's already present, set it here
-			spanParentCtx := trace.SpanFromContext(ctx).SpanContext()
-			ctx, _ = log.FromContext(ctx,
-				string(FieldTraceID), spanParentCtx.TraceID().String(),
-				string(FieldSpanID), spanParentCtx.SpanID().String(),
-			)
 		}
 
 		meter := GetMeter(rootCtx)
@@ -299,12 +292,6 @@ func ObsNatsConsumerMessageHandler(rootCtx context.Context, fn func(context.Cont
 		ctx := context.Background()
 		if msg.Headers().Get(TraceparentHeader) != "" {
 			ctx = otel.GetTextMapPropagator().Extract(rootCtx, NatsCarrier(msg.Headers()))
-			// oti.Span does not set traceID spanID to the context logger, if it's already present, set it here
-			spanParentCtx := trace.SpanFromContext(ctx).SpanContext()
-			ctx, _ = log.FromContext(ctx,
-				string(FieldTraceID), spanParentCtx.TraceID().String(),
-				string(FieldSpanID), spanParentCtx.SpanID().String(),
-			)
 		}
 
 		meter := GetMeter(rootCtx)
@@ -17,8 +17,8 @@ import (
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
 	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
@@ -29,6 +29,7 @@ type Otel struct {
 	config           Config
 	wg               *sync.WaitGroup
 	prometheusServer *http.Server
+	loggerProvider   *sdklog.LoggerProvider
 }
 
 // nullWriter implements io.Writer and discards all data written to it.
@@ -95,6 +96,12 @@ func (o *Otel) Startup(ctx context.Context) context.Context {
 	// Startup Tracing
 	o.startupTracer(ctx, res)
 
+	// Startup Logs
+	if err := o.startupLogs(ctx, res); err != nil {
+		LogError(ctx, err, "failed to start up logs")
+		panic(1)
+	}
+
 	return ctx
 }
 
@@ -106,6 +113,7 @@ func (o *Otel) Shutdown(ctx context.Context) {
 	Log(ctx, 0, "Shutdown")
 	o.shutdownMetrics(ctx)
 	o.shutdownTracer(ctx)
+	o.shutdownLogs(ctx)
 }
 
 // Startup Metrics
@@ -116,15 +124,15 @@ func (o *Otel) startupMetrics(ctx context.Context, res *resource.Resource) {
 
 	switch MetricExporterType(exporterType) {
 	case MetricExporterTypeOTLP:
-		meterProvider = must.MustVal(initOtlpMeterProvider(ctx, res))
+		meterProvider = must.MustVal(initOtlpMeterProvider(ctx, must.MustVal(o.config.otlpExporterConfig()), res, o.config))
 
 	case MetricExporterTypePrometheus:
-		meterProvider = must.MustVal(initPrometheusMeterProvider(ctx, res))
+		meterProvider = must.MustVal(initPrometheusMeterProvider(ctx, res, o.config))
 
 		if o.config.OtelExporterPrometheusPort > 0 {
 			_, cancelCtx := context.WithCancel(context.Background())
 			mux := http.NewServeMux()
-			mux.Handle("/metrics", promhttp.Handler())
+			mux.Handle("/metrics", MetricsHandler())
 			o.prometheusServer = &http.Server{
 				Addr:    fmt.Sprintf(":%d", o.config.OtelExporterPrometheusPort),
 				Handler: mux,
@@ -145,11 +153,11 @@ func (o *Otel) startupMetrics(ctx context.Context, res *resource.Resource) {
 		}
 
 	case MetricExporterTypeConsole:
-		meterProvider = must.MustVal(initConsoleMeterProvider(res, ConsoleStdout))
+		meterProvider = must.MustVal(initConsoleMeterProvider(res, ConsoleStdout, o.config))
 
 	case MetricExporterTypeNone:
 		// Use no-op provider
-		meterProvider = must.MustVal(initConsoleMeterProvider(res, ConsoleNone))
+		meterProvider = must.MustVal(initConsoleMeterProvider(res, ConsoleNone, o.config))
 	default:
 		LogError(ctx, ErrOtelConfig, "wrong metric exporter type", "otel-metric-exporter", o.config.OtelMetricsExporter)
 		panic(1)
@@ -158,6 +166,11 @@ func (o *Otel) startupMetrics(ctx context.Context, res *resource.Resource) {
 	otel.SetMeterProvider(meterProvider)
 }
 
+// MetricsHandler returns with the HTTP handler that serves the metrics collected by the Prometheus exporter
+func MetricsHandler() http.Handler {
+	return promhttp.Handler()
+}
+
 // Shutdown Metrics
 func (o *Otel) shutdownMetrics(ctx context.Context) {
 	ctx = LogWithValues(ctx, FieldComponent, "Otel.Metrics")
@@ -184,7 +197,7 @@ func (o *Otel) startupTracer(ctx context.Context, res *resource.Resource) contex
 	var tracerProvider *sdktrace.TracerProvider
 	switch TraceExporterType(exporterType) {
 	case TraceExporterTypeOTLP:
-		tracerProvider = must.MustVal(initTracerProvider(ctx, must.MustVal(otlptracegrpc.New(ctx)), res))
+		tracerProvider = must.MustVal(initTracerProvider(ctx, must.MustVal(newOtlpTraceExporter(ctx, must.MustVal(o.config.otlpExporterConfig()))), res, o.config))
 
 		/*
 			case "jaeger":
@@ -195,11 +208,11 @@ func (o *Otel) startupTracer(ctx context.Context, res *resource.Resource) contex
 		*/
 
 	case TraceExporterTypeConsole:
-		tracerProvider = must.MustVal(initTracerProvider(ctx, must.MustVal(stdouttrace.New(stdouttrace.WithPrettyPrint())), res))
+		tracerProvider = must.MustVal(initTracerProvider(ctx, must.MustVal(stdouttrace.New(stdouttrace.WithPrettyPrint())), res, o.config))
 
 	case TraceExporterTypeNone, "":
 		// Use no-op provider
-		tracerProvider = must.MustVal(initTracerProvider(ctx, must.MustVal(stdouttrace.New(stdouttrace.WithWriter(nullWriter{}))), res))
+		tracerProvider = must.MustVal(initTracerProvider(ctx, must.MustVal(stdouttrace.New(stdouttrace.WithWriter(nullWriter{}))), res, o.config))
 	default:
 		LogError(ctx, ErrOtelConfig, "wrong tracer exporter type", "otel-traces-exporter", o.config.OtelTracesExporter)
 		panic(1)
@@ -0,0 +1,278 @@
+package oti
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc/credentials"
+)
+
+const (
+	// The protocols of the OTLP exporters
+	OtlpProtocolGRPC         = "grpc"
+	OtlpProtocolHTTPProtobuf = "http/protobuf"
+
+	// The compressions of the OTLP exporters
+	OtlpCompressionGzip = "gzip"
+	OtlpCompressionNone = "none"
+
+	// The URL paths of the signals appended to the path of the http/protobuf endpoint
+	otlpTracesPath  = "v1/traces"
+	otlpMetricsPath = "v1/metrics"
+	otlpLogsPath    = "v1/logs"
+)
+
+// otlpExporterConfig is the parsed configuration of the OTLP exporters of the traces, metrics and logs
+type otlpExporterConfig struct {
+	protocol string
+	// endpoint is the host and port of the endpoint, the default of the protocol is used if empty
+	endpoint string
+	// urlPath is the path of the http/protobuf endpoint, that the paths of the signals are appended to
+	urlPath  string
+	insecure bool
+	headers  map[string]string
+	gzip     bool
+	// timeout is the timeout of the exports, the default of the exporter is used if 0
+	timeout   time.Duration
+	tlsConfig *tls.Config
+}
+
+// otlpExporterConfig parses and validates the OTLP exporter parameters of the config
+func (cfg *Config) otlpExporterConfig() (otlpExporterConfig, error) {
+	otlp := otlpExporterConfig{
+		protocol: strings.ToLower(cfg.OtelExporterOtlpProtocol),
+		insecure: cfg.OtelExporterOtlpInsecure,
+		timeout:  time.Duration(cfg.OtelExporterOtlpTimeout) * time.Millisecond,
+	}
+	if otlp.protocol != OtlpProtocolGRPC && otlp.protocol != OtlpProtocolHTTPProtobuf {
+		return otlp, fmt.Errorf("%w: wrong OTLP exporter protocol: %s", ErrOtelConfig, cfg.OtelExporterOtlpProtocol)
+	}
+	if cfg.OtelExporterOtlpTimeout < 0 {
+		return otlp, fmt.Errorf("%w: negative OTLP exporter timeout: %d", ErrOtelConfig, cfg.OtelExporterOtlpTimeout)
+	}
+
+	switch compression := strings.ToLower(cfg.OtelExporterOtlpCompression); compression {
+	case OtlpCompressionGzip:
+		otlp.gzip = true
+	case OtlpCompressionNone, "":
+	default:
+		return otlp, fmt.Errorf("%w: wrong OTLP exporter compression: %s", ErrOtelConfig, cfg.OtelExporterOtlpCompression)
+	}
+
+	// The endpoint is either a URL, e.g. https://collector:4318/otlp, or a host and port, e.g. collector:4317
+	if endpoint := cfg.OtelExporterOtlpEndpoint; strings.Contains(endpoint, "://") {
+		u, err := url.Parse(endpoint)
+		if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+			return otlp, fmt.Errorf("%w: wrong OTLP exporter endpoint: %s", ErrOtelConfig, endpoint)
+		}
+		otlp.endpoint, otlp.urlPath = u.Host, u.Path
+		otlp.insecure = otlp.insecure || u.Scheme == "http"
+	} else {
+		otlp.endpoint = endpoint
+	}
+
+	headers, err := parseOtlpHeaders(cfg.OtelExporterOtlpHeaders)
+	if err != nil {
+		return otlp, err
+	}
+	otlp.headers = headers
+
+	if cfg.OtelExporterOtlpCertificate != "" {
+		if otlp.insecure {
+			return otlp, fmt.Errorf("%w: the OTLP exporter certificate is set for an insecure endpoint", ErrOtelConfig)
+		}
+		pem, err := os.ReadFile(cfg.OtelExporterOtlpCertificate)
+		if err != nil {
+			return otlp, fmt.Errorf("failed to read the OTLP exporter certificate. %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return otlp, fmt.Errorf("%w: no certificates in the OTLP exporter certificate file: %s", ErrOtelConfig, cfg.OtelExporterOtlpCertificate)
+		}
+		otlp.tlsConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+	return otlp, nil
+}
+
+// parseOtlpHeaders parses the headers in the format of OTEL_EXPORTER_OTLP_HEADERS, e.g. api-key=secret,tenant=acme
+func parseOtlpHeaders(s string) (map[string]string, error) {
+	headers := map[string]string{}
+	for _, pair := range strings.Split(s, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("%w: wrong OTLP exporter header: %s", ErrOtelConfig, pair)
+		}
+		value, err := url.PathUnescape(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("%w: wrong OTLP exporter header value of %s. %w", ErrOtelConfig, key, err)
+		}
+		headers[key] = value
+	}
+	return headers, nil
+}
+
+// signalPath returns with the URL path of the signal on the http/protobuf endpoint
+func (otlp otlpExporterConfig) signalPath(signalPath string) string {
+	return path.Join("/", otlp.urlPath, signalPath)
+}
+
+// newOtlpTraceExporter creates the OTLP exporter of the traces
+func newOtlpTraceExporter(ctx context.Context, otlp otlpExporterConfig) (sdktrace.SpanExporter, error) {
+	if otlp.protocol == OtlpProtocolHTTPProtobuf {
+		opts := []otlptracehttp.Option{}
+		if otlp.endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(otlp.endpoint), otlptracehttp.WithURLPath(otlp.signalPath(otlpTracesPath)))
+		}
+		if otlp.insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		if otlp.tlsConfig != nil {
+			opts = append(opts, otlptracehttp.WithTLSClientConfig(otlp.tlsConfig))
+		}
+		if len(otlp.headers) > 0 {
+			opts = append(opts, otlptracehttp.WithHeaders(otlp.headers))
+		}
+		if otlp.timeout > 0 {
+			opts = append(opts, otlptracehttp.WithTimeout(otlp.timeout))
+		}
+		if otlp.gzip {
+			opts = append(opts, otlptracehttp.WithCompression(otlptracehttp.GzipCompression))
+		}
+		return otlptracehttp.New(ctx, opts...) //nolint:wrapcheck // wrapped by the caller
+	}
+
+	opts := []otlptracegrpc.Option{}
+	if otlp.endpoint != "" {
+		opts = append(opts, otlptracegrpc.WithEndpoint(otlp.endpoint))
+	}
+	if otlp.insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+	if otlp.tlsConfig != nil {
+		opts = append(opts, otlptracegrpc.WithTLSCredentials(credentials.NewTLS(otlp.tlsConfig)))
+	}
+	if len(otlp.headers) > 0 {
+		opts = append(opts, otlptracegrpc.WithHeaders(otlp.headers))
+	}
+	if otlp.timeout > 0 {
+		opts = append(opts, otlptracegrpc.WithTimeout(otlp.timeout))
+	}
+	if otlp.gzip {
+		opts = append(opts, otlptracegrpc.WithCompressor(OtlpCompressionGzip))
+	}
+	return otlptracegrpc.New(ctx, opts...) //nolint:wrapcheck // wrapped by the caller
+}
+
+// newOtlpMetricExporter creates the OTLP exporter of the metrics
+func newOtlpMetricExporter(ctx context.Context, otlp otlpExporterConfig) (sdkmetric.Exporter, error) {
+	if otlp.protocol == OtlpProtocolHTTPProtobuf {
+		opts := []otlpmetrichttp.Option{}
+		if otlp.endpoint != "" {
+			opts = append(opts, otlpmetrichttp.WithEndpoint(otlp.endpoint), otlpmetrichttp.WithURLPath(otlp.signalPath(otlpMetricsPath)))
+		}
+		if otlp.insecure {
+			opts = append(opts, otlpmetrichttp.WithInsecure())
+		}
+		if otlp.tlsConfig != nil {
+			opts = append(opts, otlpmetrichttp.WithTLSClientConfig(otlp.tlsConfig))
+		}
+		if len(otlp.headers) > 0 {
+			opts = append(opts, otlpmetrichttp.WithHeaders(otlp.headers))
+		}
+		if otlp.timeout > 0 {
+			opts = append(opts, otlpmetrichttp.WithTimeout(otlp.timeout))
+		}
+		if otlp.gzip {
+			opts = append(opts, otlpmetrichttp.WithCompression(otlpmetrichttp.GzipCompression))
+		}
+		return otlpmetrichttp.New(ctx, opts...) //nolint:wrapcheck // wrapped by the caller
+	}
+
+	opts := []otlpmetricgrpc.Option{}
+	if otlp.endpoint != "" {
+		opts = append(opts, otlpmetricgrpc.WithEndpoint(otlp.endpoint))
+	}
+	if otlp.insecure {
+		opts = append(opts, otlpmetricgrpc.WithInsecure())
+	}
+	if otlp.tlsConfig != nil {
+		opts = append(opts, otlpmetricgrpc.WithTLSCredentials(credentials.NewTLS(otlp.tlsConfig)))
+	}
+	if len(otlp.headers) > 0 {
+		opts = append(opts, otlpmetricgrpc.WithHeaders(otlp.headers))
+	}
+	if otlp.timeout > 0 {
+		opts = append(opts, otlpmetricgrpc.WithTimeout(otlp.timeout))
+	}
+	if otlp.gzip {
+		opts = append(opts, otlpmetricgrpc.WithCompressor(OtlpCompressionGzip))
+	}
+	return otlpmetricgrpc.New(ctx, opts...) //nolint:wrapcheck // wrapped by the caller
+}
+
+// newOtlpLogExporter creates the OTLP exporter of the logs
+func newOtlpLogExporter(ctx context.Context, otlp otlpExporterConfig) (sdklog.Exporter, error) {
+	if otlp.protocol == OtlpProtocolHTTPProtobuf {
+		opts := []otlploghttp.Option{}
+		if otlp.endpoint != "" {
+			opts = append(opts, otlploghttp.WithEndpoint(otlp.endpoint), otlploghttp.WithURLPath(otlp.signalPath(otlpLogsPath)))
+		}
+		if otlp.insecure {
+			opts = append(opts, otlploghttp.WithInsecure())
+		}
+		if otlp.tlsConfig != nil {
+			opts = append(opts, otlploghttp.WithTLSClientConfig(otlp.tlsConfig))
+		}
+		if len(otlp.headers) > 0 {
+			opts = append(opts, otlploghttp.WithHeaders(otlp.headers))
+		}
+		if otlp.timeout > 0 {
+			opts = append(opts, otlploghttp.WithTimeout(otlp.timeout))
+		}
+		if otlp.gzip {
+			opts = append(opts, otlploghttp.WithCompression(otlploghttp.GzipCompression))
+		}
+		return otlploghttp.New(ctx, opts...) //nolint:wrapcheck // wrapped by the caller
+	}
+
+	opts := []otlploggrpc.Option{}
+	if otlp.endpoint != "" {
+		opts = append(opts, otlploggrpc.WithEndpoint(otlp.endpoint))
+	}
+	if otlp.insecure {
+		opts = append(opts, otlploggrpc.WithInsecure())
+	}
+	if otlp.tlsConfig != nil {
+		opts = append(opts, otlploggrpc.WithTLSCredentials(credentials.NewTLS(otlp.tlsConfig)))
+	}
+	if len(otlp.headers) > 0 {
+		opts = append(opts, otlploggrpc.WithHeaders(otlp.headers))
+	}
+	if otlp.timeout > 0 {
+		opts = append(opts, otlploggrpc.WithTimeout(otlp.timeout))
+	}
+	if otlp.gzip {
+		opts = append(opts, otlploggrpc.WithCompressor(OtlpCompressionGzip))
+	}
+	return otlploggrpc.New(ctx, opts...) //nolint:wrapcheck // wrapped by the caller
+}
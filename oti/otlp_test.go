@@ -0,0 +1,127 @@
+package oti
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestOtlpExporterConfig(t *testing.T) {
+	testCases := map[string]struct {
+		config   Config
+		expected otlpExporterConfig
+	}{
+		"defaults": {
+			config:   Config{OtelExporterOtlpProtocol: OtlpProtocolGRPC},
+			expected: otlpExporterConfig{protocol: OtlpProtocolGRPC, headers: map[string]string{}},
+		},
+		"host and port": {
+			config: Config{
+				OtelExporterOtlpEndpoint:    "collector:4317",
+				OtelExporterOtlpProtocol:    OtlpProtocolGRPC,
+				OtelExporterOtlpInsecure:    true,
+				OtelExporterOtlpCompression: OtlpCompressionGzip,
+				OtelExporterOtlpTimeout:     500,
+			},
+			expected: otlpExporterConfig{
+				protocol: OtlpProtocolGRPC, endpoint: "collector:4317", insecure: true, gzip: true,
+				headers: map[string]string{}, timeout: 500 * time.Millisecond,
+			},
+		},
+		"http url with path and headers": {
+			config: Config{
+				OtelExporterOtlpEndpoint: "http://collector:4318/otlp",
+				OtelExporterOtlpProtocol: "HTTP/protobuf",
+				OtelExporterOtlpHeaders:  "api-key=secret%3D1, tenant = acme",
+			},
+			expected: otlpExporterConfig{
+				protocol: OtlpProtocolHTTPProtobuf, endpoint: "collector:4318", urlPath: "/otlp", insecure: true,
+				headers: map[string]string{"api-key": "secret=1", "tenant": "acme"},
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			otlp, err := testCase.config.otlpExporterConfig()
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, otlp)
+		})
+	}
+}
+
+func TestOtlpExporterConfigErrors(t *testing.T) {
+	testCases := map[string]Config{
+		"protocol":                  {OtelExporterOtlpProtocol: "thrift"},
+		"compression":               {OtelExporterOtlpProtocol: OtlpProtocolGRPC, OtelExporterOtlpCompression: "zstd"},
+		"timeout":                   {OtelExporterOtlpProtocol: OtlpProtocolGRPC, OtelExporterOtlpTimeout: -1},
+		"endpoint scheme":           {OtelExporterOtlpProtocol: OtlpProtocolGRPC, OtelExporterOtlpEndpoint: "ftp://collector"},
+		"header":                    {OtelExporterOtlpProtocol: OtlpProtocolGRPC, OtelExporterOtlpHeaders: "api-key"},
+		"header value":              {OtelExporterOtlpProtocol: OtlpProtocolGRPC, OtelExporterOtlpHeaders: "api-key=%zz"},
+		"missing certificate":       {OtelExporterOtlpProtocol: OtlpProtocolGRPC, OtelExporterOtlpCertificate: "missing.pem"},
+		"insecure with certificate": {OtelExporterOtlpProtocol: OtlpProtocolGRPC, OtelExporterOtlpInsecure: true, OtelExporterOtlpCertificate: "ca.pem"},
+	}
+
+	for name, config := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := config.otlpExporterConfig()
+			assert.Error(t, err)
+		})
+	}
+
+	t.Run("not a certificate", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "ca.pem")
+		require.NoError(t, os.WriteFile(file, []byte("not a certificate"), 0o600))
+		_, err := (&Config{OtelExporterOtlpProtocol: OtlpProtocolGRPC, OtelExporterOtlpCertificate: file}).otlpExporterConfig()
+		assert.ErrorIs(t, err, ErrOtelConfig)
+	})
+}
+
+func TestLoadConfigValidatesOtlpExporter(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", "thrift")
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	cfg := &Config{}
+	cfg.GetConfigFlagSet(fs)
+
+	assert.ErrorIs(t, cfg.LoadConfig(fs), ErrOtelConfig)
+}
+
+func TestOtlpTraceExporterHTTP(t *testing.T) {
+	requests := make(chan *http.Request, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case requests <- r:
+		default:
+		}
+	}))
+	defer server.Close()
+
+	otlp, err := (&Config{
+		OtelExporterOtlpEndpoint:    server.URL + "/otlp",
+		OtelExporterOtlpProtocol:    OtlpProtocolHTTPProtobuf,
+		OtelExporterOtlpHeaders:     "api-key=secret",
+		OtelExporterOtlpCompression: OtlpCompressionGzip,
+	}).otlpExporterConfig()
+	require.NoError(t, err)
+	exporter, err := newOtlpTraceExporter(context.Background(), otlp)
+	require.NoError(t, err)
+
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	_, span := tracerProvider.Tracer("test").Start(context.Background(), "test")
+	span.End()
+	require.NoError(t, tracerProvider.Shutdown(context.Background()))
+
+	r := <-requests
+	assert.Equal(t, "/otlp/v1/traces", r.URL.Path)
+	assert.Equal(t, "secret", r.Header.Get("api-key"))
+	assert.Equal(t, "gzip", r.Header.Get("Content-Encoding"))
+}
@@ -0,0 +1,204 @@
+package oti
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// The samplers of the traces, see the OTEL_TRACES_SAMPLER of the OpenTelemetry specification
+	SamplerAlwaysOn                = "always_on"
+	SamplerAlwaysOff               = "always_off"
+	SamplerTraceIDRatio            = "traceidratio"
+	SamplerParentBasedAlwaysOn     = "parentbased_always_on"
+	SamplerParentBasedAlwaysOff    = "parentbased_always_off"
+	SamplerParentBasedTraceIDRatio = "parentbased_traceidratio"
+
+	// The actions of the sampling rules, see ParseSamplingRules
+	SamplingRuleSample = "sample"
+	SamplingRuleDrop   = "drop"
+)
+
+// samplingRuleKeys are the attributes of the spans matched by the sampling rules besides the span name
+var samplingRuleKeys = []attribute.Key{"http.route", "http.target", "url.path", "url.template"}
+
+// NewSampler creates the sampler of the traces by its name, e.g. parentbased_traceidratio, or parentbased_always_on if the name is empty.
+// The arg is the ratio of the sampled traces of the ratio based samplers, between 0 and 1.
+func NewSampler(name string, arg float64) (sdktrace.Sampler, error) {
+	isRatio := name == SamplerTraceIDRatio || name == SamplerParentBasedTraceIDRatio
+	if isRatio && (arg < 0 || arg > 1) {
+		return nil, fmt.Errorf("%w: the traces sampler arg must be between 0 and 1: %v", ErrOtelConfig, arg)
+	}
+
+	switch name {
+	case SamplerAlwaysOn:
+		return sdktrace.AlwaysSample(), nil
+	case SamplerAlwaysOff:
+		return sdktrace.NeverSample(), nil
+	case SamplerTraceIDRatio:
+		return sdktrace.TraceIDRatioBased(arg), nil
+	case SamplerParentBasedAlwaysOn, "":
+		return sdktrace.ParentBased(sdktrace.AlwaysSample()), nil
+	case SamplerParentBasedAlwaysOff:
+		return sdktrace.ParentBased(sdktrace.NeverSample()), nil
+	case SamplerParentBasedTraceIDRatio:
+		return sdktrace.ParentBased(sdktrace.TraceIDRatioBased(arg)), nil
+	default:
+		return nil, fmt.Errorf("%w: wrong traces sampler: %s", ErrOtelConfig, name)
+	}
+}
+
+// traceSampler creates the sampler of the traces configured by the OtelTracesSampler* parameters
+func (cfg *Config) traceSampler() (sdktrace.Sampler, error) {
+	sampler, err := NewSampler(strings.ToLower(cfg.OtelTracesSampler), cfg.OtelTracesSamplerArg)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := ParseSamplingRules(cfg.OtelTracesSamplerRules)
+	if err != nil {
+		return nil, err
+	}
+	// Only the spans dropped by the sampler are recorded for the errors, the ones dropped by the rules are not
+	if cfg.OtelTracesSamplerErrors {
+		sampler = NewErrorSampler(sampler)
+	}
+	if len(rules) > 0 {
+		sampler = NewRuleSampler(sampler, rules...)
+	}
+	return sampler, nil
+}
+
+// SamplingRule samples or drops the spans, whose name or route matches the pattern
+type SamplingRule struct {
+	// Pattern is a path.Match pattern of the span name, or the http.route, http.target, url.path or url.template attribute, e.g. /health*
+	Pattern string
+	// Sample tells whether the matching spans are sampled or dropped
+	Sample bool
+}
+
+// ParseSamplingRules parses the comma separated `action:pattern` rules, where the action is sample or drop,
+// e.g. `drop:/healthz,drop:/readyz,sample:/orders/*`
+func ParseSamplingRules(s string) ([]SamplingRule, error) {
+	rules := []SamplingRule{}
+	for _, spec := range strings.Split(s, ",") {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+		action, pattern, ok := strings.Cut(spec, ":")
+		if !ok || pattern == "" || (action != SamplingRuleSample && action != SamplingRuleDrop) {
+			return nil, fmt.Errorf("%w: wrong traces sampling rule: %s", ErrOtelConfig, spec)
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("%w: wrong traces sampling rule pattern: %s. %w", ErrOtelConfig, spec, err)
+		}
+		rules = append(rules, SamplingRule{Pattern: pattern, Sample: action == SamplingRuleSample})
+	}
+	return rules, nil
+}
+
+// matches tells whether the span name or one of the route attributes matches the pattern of the rule
+func (r SamplingRule) matches(p sdktrace.SamplingParameters) bool {
+	if ok, _ := path.Match(r.Pattern, p.Name); ok {
+		return true
+	}
+	for _, attr := range p.Attributes {
+		for _, key := range samplingRuleKeys {
+			if attr.Key == key {
+				if ok, _ := path.Match(r.Pattern, attr.Value.Emit()); ok {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// ruleSampler is the sampler created by NewRuleSampler
+type ruleSampler struct {
+	fallback sdktrace.Sampler
+	rules    []SamplingRule
+}
+
+// NewRuleSampler creates a sampler, that samples or drops the spans by the first matching rule,
+// and samples the other spans by the fallback sampler.
+// The rules are applied to the child spans too, e.g. the spans of the health check routes are dropped regardless of their parents.
+func NewRuleSampler(fallback sdktrace.Sampler, rules ...SamplingRule) sdktrace.Sampler {
+	return &ruleSampler{fallback: fallback, rules: rules}
+}
+
+func (s *ruleSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	for _, rule := range s.rules {
+		if rule.matches(p) {
+			decision := sdktrace.Drop
+			if rule.Sample {
+				decision = sdktrace.RecordAndSample
+			}
+			return sdktrace.SamplingResult{Decision: decision, Tracestate: trace.SpanContextFromContext(p.ParentContext).TraceState()}
+		}
+	}
+	return s.fallback.ShouldSample(p)
+}
+
+func (s *ruleSampler) Description() string {
+	return fmt.Sprintf("RuleSampler{rules:%d,fallback:%s}", len(s.rules), s.fallback.Description())
+}
+
+// errorSampler is the sampler created by NewErrorSampler
+type errorSampler struct {
+	sampler sdktrace.Sampler
+}
+
+// NewErrorSampler wraps the sampler to record the spans it drops, so the span processor created by NewErrorSpanProcessor
+// can export them if they end with error. The recorded spans are not propagated as sampled to the other services.
+// Every dropped span is recorded in memory until it ends, so the cost of recording is the same as if every span was sampled,
+// only the export is saved.
+func NewErrorSampler(sampler sdktrace.Sampler) sdktrace.Sampler {
+	return &errorSampler{sampler: sampler}
+}
+
+func (s *errorSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	result := s.sampler.ShouldSample(p)
+	if result.Decision == sdktrace.Drop {
+		result.Decision = sdktrace.RecordOnly
+	}
+	return result
+}
+
+func (s *errorSampler) Description() string {
+	return fmt.Sprintf("ErrorSampler{%s}", s.sampler.Description())
+}
+
+// errorSpanProcessor is the span processor created by NewErrorSpanProcessor
+type errorSpanProcessor struct {
+	sdktrace.SpanProcessor
+}
+
+// NewErrorSpanProcessor wraps the span processor to also process the recorded, but not sampled spans that end with error status,
+// as if they were sampled, see NewErrorSampler
+func NewErrorSpanProcessor(next sdktrace.SpanProcessor) sdktrace.SpanProcessor {
+	return &errorSpanProcessor{SpanProcessor: next}
+}
+
+func (p *errorSpanProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	if !s.SpanContext().IsSampled() && s.Status().Code == codes.Error {
+		s = sampledSpan{ReadOnlySpan: s}
+	}
+	p.SpanProcessor.OnEnd(s)
+}
+
+// sampledSpan is a recorded span, that is processed as sampled
+type sampledSpan struct {
+	sdktrace.ReadOnlySpan
+}
+
+func (s sampledSpan) SpanContext() trace.SpanContext {
+	sc := s.ReadOnlySpan.SpanContext()
+	return sc.WithTraceFlags(sc.TraceFlags().WithSampled(true))
+}
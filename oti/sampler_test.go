@@ -0,0 +1,123 @@
+package oti
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestNewSampler(t *testing.T) {
+	for _, name := range []string{SamplerAlwaysOn, SamplerAlwaysOff, SamplerTraceIDRatio, SamplerParentBasedAlwaysOn, SamplerParentBasedAlwaysOff, SamplerParentBasedTraceIDRatio, ""} {
+		sampler, err := NewSampler(name, 0.5)
+		require.NoError(t, err, name)
+		assert.NotNil(t, sampler, name)
+	}
+
+	_, err := NewSampler("jaeger_remote", 1)
+	assert.ErrorIs(t, err, ErrOtelConfig)
+	_, err = NewSampler(SamplerParentBasedTraceIDRatio, 1.5)
+	assert.ErrorIs(t, err, ErrOtelConfig)
+}
+
+func TestParseSamplingRules(t *testing.T) {
+	rules, err := ParseSamplingRules(" drop:/healthz, sample:/orders/*,")
+	require.NoError(t, err)
+	assert.Equal(t, []SamplingRule{{Pattern: "/healthz"}, {Pattern: "/orders/*", Sample: true}}, rules)
+
+	for _, spec := range []string{"drop", "keep:/healthz", "drop:", "drop:/health[z"} {
+		_, err := ParseSamplingRules(spec)
+		assert.ErrorIs(t, err, ErrOtelConfig, spec)
+	}
+}
+
+func TestRuleSampler(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tracerProvider := sdktrace.NewTracerProvider(
+		sdktrace.WithSyncer(exporter),
+		sdktrace.WithSampler(NewRuleSampler(sdktrace.NeverSample(),
+			SamplingRule{Pattern: "/healthz*"},
+			SamplingRule{Pattern: "/orders/*", Sample: true},
+			SamplingRule{Pattern: "/*", Sample: true},
+		)),
+	)
+	tracer := tracerProvider.Tracer("test")
+
+	for _, target := range []string{"/healthz?verbose", "/orders/42", "/users/42"} {
+		_, span := tracer.Start(context.Background(), "IN HTTP GET "+target, trace.WithAttributes(attribute.String("http.target", target)))
+		span.End()
+	}
+	_, span := tracer.Start(context.Background(), "process")
+	span.End()
+
+	names := []string{}
+	for _, span := range exporter.GetSpans() {
+		names = append(names, span.Name)
+	}
+	assert.Equal(t, []string{"IN HTTP GET /orders/42"}, names)
+}
+
+func TestErrorSampler(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tracerProvider := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(NewErrorSpanProcessor(sdktrace.NewSimpleSpanProcessor(exporter))),
+		sdktrace.WithSampler(NewErrorSampler(sdktrace.NeverSample())),
+	)
+	tracer := tracerProvider.Tracer("test")
+
+	_, span := tracer.Start(context.Background(), "ok")
+	assert.False(t, span.SpanContext().IsSampled())
+	span.End()
+	_, span = tracer.Start(context.Background(), "failed")
+	span.RecordError(errors.New("boom"))
+	span.SetStatus(codes.Error, "boom")
+	span.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "failed", spans[0].Name)
+	assert.True(t, spans[0].SpanContext.IsSampled())
+}
+
+func TestErrorSamplerKeepsRuleDrops(t *testing.T) {
+	sampler, err := (&Config{OtelTracesSampler: SamplerAlwaysOff, OtelTracesSamplerRules: "drop:/healthz", OtelTracesSamplerErrors: true}).traceSampler()
+	require.NoError(t, err)
+	exporter := tracetest.NewInMemoryExporter()
+	tracerProvider := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(NewErrorSpanProcessor(sdktrace.NewSimpleSpanProcessor(exporter))),
+		sdktrace.WithSampler(sampler),
+	)
+	tracer := tracerProvider.Tracer("test")
+
+	// The span dropped by the rule is not even recorded, the one dropped by the sampler is exported on error
+	for _, target := range []string{"/healthz", "/orders"} {
+		_, span := tracer.Start(context.Background(), "IN HTTP GET "+target, trace.WithAttributes(attribute.String("http.target", target)))
+		assert.Equal(t, target == "/orders", span.IsRecording(), target)
+		span.SetStatus(codes.Error, "boom")
+		span.End()
+	}
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "IN HTTP GET /orders", spans[0].Name)
+}
+
+func TestConfigTraceSampler(t *testing.T) {
+	sampler, err := (&Config{OtelTracesSampler: "TraceIDRatio", OtelTracesSamplerArg: 0.1}).traceSampler()
+	require.NoError(t, err)
+	assert.Equal(t, sdktrace.TraceIDRatioBased(0.1).Description(), sampler.Description())
+
+	sampler, err = (&Config{OtelTracesSampler: SamplerAlwaysOn, OtelTracesSamplerRules: "drop:/healthz", OtelTracesSamplerErrors: true}).traceSampler()
+	require.NoError(t, err)
+	assert.Equal(t, "RuleSampler{rules:1,fallback:ErrorSampler{AlwaysOnSampler}}", sampler.Description())
+
+	_, err = (&Config{OtelTracesSampler: SamplerAlwaysOn, OtelTracesSamplerRules: "keep:/healthz"}).traceSampler()
+	assert.ErrorIs(t, err, ErrOtelConfig)
+}
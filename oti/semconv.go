@@ -19,6 +19,7 @@ List of label filters must be configured in Telemetry Gateway config (logs.*.ote
 
 const (
 	// Log fields
+	// KeyError is the message of the error attribute of log.ErrorAttr
 	KeyError      = "error.message"
 	KeyMetricName = "metric.name"
 	KeyTestSuite  = "test.suite"
@@ -0,0 +1,129 @@
+package oti
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	metric_api "go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	"github.com/tombenke/go-12f-common/v2/buildinfo"
+)
+
+const (
+	// MetricDroppedSpans is the counter of the spans dropped by the span processor, see FieldDropReason
+	MetricDroppedSpans = "trace.spans.dropped"
+
+	// FieldDropReason is the reason of the dropped spans: queue_full or export_failed
+	FieldDropReason = attribute.Key("reason")
+
+	DropReasonQueueFull    = "queue_full"
+	DropReasonExportFailed = "export_failed"
+)
+
+// BatchConfig is the configuration of the batch span processor, see the OTEL_BSP_* of the OpenTelemetry specification
+type BatchConfig struct {
+	// ScheduleDelay is the max delay between two exports
+	ScheduleDelay time.Duration
+	// ExportTimeout is the timeout of the exports
+	ExportTimeout time.Duration
+	// MaxQueueSize is the max number of the spans waiting for export, the new spans are dropped if the queue is full
+	MaxQueueSize int
+	// MaxExportBatchSize is the max number of the spans exported at once
+	MaxExportBatchSize int
+	// Sync exports the spans synchronously when they end, e.g. in the tests
+	Sync bool
+}
+
+// batchConfig returns with the batching config of the traces. The parameters of 0 are replaced by their defaults.
+func (cfg *Config) batchConfig() (BatchConfig, error) {
+	if cfg.OtelBspScheduleDelay < 0 || cfg.OtelBspExportTimeout < 0 || cfg.OtelBspMaxQueueSize < 0 || cfg.OtelBspMaxExportBatchSize < 0 {
+		return BatchConfig{}, fmt.Errorf("%w: the batch span processor parameters must not be negative", ErrOtelConfig)
+	}
+	batch := BatchConfig{
+		ScheduleDelay:      time.Duration(cmp.Or(cfg.OtelBspScheduleDelay, OTEL_BSP_SCHEDULE_DELAY_DEFAULT)) * time.Millisecond,
+		ExportTimeout:      time.Duration(cmp.Or(cfg.OtelBspExportTimeout, OTEL_BSP_EXPORT_TIMEOUT_DEFAULT)) * time.Millisecond,
+		MaxQueueSize:       cmp.Or(cfg.OtelBspMaxQueueSize, OTEL_BSP_MAX_QUEUE_SIZE_DEFAULT),
+		MaxExportBatchSize: cmp.Or(cfg.OtelBspMaxExportBatchSize, OTEL_BSP_MAX_EXPORT_BATCH_SIZE_DEFAULT),
+		Sync:               cfg.OtelTracesSyncExport,
+	}
+	if batch.MaxExportBatchSize > batch.MaxQueueSize {
+		return batch, fmt.Errorf("%w: the max export batch size %d is greater than the max queue size %d",
+			ErrOtelConfig, batch.MaxExportBatchSize, batch.MaxQueueSize)
+	}
+	return batch, nil
+}
+
+// NewSpanProcessor creates the span processor, that exports the spans by the exporter in batches, or synchronously.
+// The spans dropped because the queue is full, or the export failed are counted by the trace.spans.dropped metric.
+func NewSpanProcessor(exporter sdktrace.SpanExporter, config BatchConfig) sdktrace.SpanProcessor {
+	// The global meter provider delegates to the one set up by startupMetrics
+	counter, err := otel.GetMeterProvider().Meter(buildinfo.ModulePath(NewSpanProcessor)).Int64Counter(
+		MetricDroppedSpans,
+		metric_api.WithDescription("The number of the spans dropped by the span processor"),
+	)
+	if err != nil {
+		counter = noop.Int64Counter{}
+	}
+
+	counting := &countingExporter{SpanExporter: exporter, dropped: counter}
+	if config.Sync {
+		return sdktrace.NewSimpleSpanProcessor(counting)
+	}
+
+	processor := &queueLimitProcessor{maxQueueSize: int64(config.MaxQueueSize), dropped: counter}
+	counting.onExport = func(n int) { processor.queued.Add(-int64(n)) }
+	processor.SpanProcessor = sdktrace.NewBatchSpanProcessor(counting,
+		sdktrace.WithBatchTimeout(config.ScheduleDelay),
+		sdktrace.WithExportTimeout(config.ExportTimeout),
+		sdktrace.WithMaxQueueSize(config.MaxQueueSize),
+		sdktrace.WithMaxExportBatchSize(config.MaxExportBatchSize),
+	)
+	return processor
+}
+
+// queueLimitProcessor counts the spans passed to the batch span processor, but not exported yet,
+// and drops the new spans if their number reaches the size of the queue, so the batch span processor never drops them silently
+type queueLimitProcessor struct {
+	sdktrace.SpanProcessor
+	maxQueueSize int64
+	queued       atomic.Int64
+	dropped      metric_api.Int64Counter
+}
+
+func (p *queueLimitProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	if !s.SpanContext().IsSampled() {
+		return
+	}
+	if p.queued.Add(1) > p.maxQueueSize {
+		p.queued.Add(-1)
+		p.dropped.Add(context.Background(), 1, metric_api.WithAttributes(FieldDropReason.String(DropReasonQueueFull)))
+		return
+	}
+	p.SpanProcessor.OnEnd(s)
+}
+
+// countingExporter counts the spans of the failed exports
+type countingExporter struct {
+	sdktrace.SpanExporter
+	dropped metric_api.Int64Counter
+	// onExport is called with the number of the spans before they are exported
+	onExport func(n int)
+}
+
+func (e *countingExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	if e.onExport != nil {
+		e.onExport(len(spans))
+	}
+	if err := e.SpanExporter.ExportSpans(ctx, spans); err != nil {
+		e.dropped.Add(ctx, int64(len(spans)), metric_api.WithAttributes(FieldDropReason.String(DropReasonExportFailed)))
+		return err //nolint:wrapcheck // the error is handled by the span processor
+	}
+	return nil
+}
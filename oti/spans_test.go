@@ -0,0 +1,98 @@
+package oti
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestInitTracerProviderExportsOnce(t *testing.T) {
+	for name, config := range map[string]Config{"batch": {}, "sync": {OtelTracesSyncExport: true}} {
+		t.Run(name, func(t *testing.T) {
+			exporter := tracetest.NewInMemoryExporter()
+			tracerProvider, err := initTracerProvider(context.Background(), exporter, resource.Empty(), config)
+			require.NoError(t, err)
+
+			_, span := tracerProvider.Tracer("test").Start(context.Background(), "test")
+			span.End()
+			require.NoError(t, tracerProvider.ForceFlush(context.Background()))
+
+			assert.Len(t, exporter.GetSpans(), 1)
+		})
+	}
+}
+
+func TestBatchConfig(t *testing.T) {
+	batch, err := (&Config{OtelBspMaxQueueSize: 100, OtelBspMaxExportBatchSize: 10}).batchConfig()
+	require.NoError(t, err)
+	assert.Equal(t, BatchConfig{ScheduleDelay: 5e9, ExportTimeout: 30e9, MaxQueueSize: 100, MaxExportBatchSize: 10}, batch)
+
+	_, err = (&Config{OtelBspMaxQueueSize: 100}).batchConfig()
+	assert.ErrorIs(t, err, ErrOtelConfig)
+	_, err = (&Config{OtelBspScheduleDelay: -1}).batchConfig()
+	assert.ErrorIs(t, err, ErrOtelConfig)
+}
+
+// endedSpans counts the spans passed to the processor
+type endedSpans struct {
+	sdktrace.SpanProcessor
+	count int
+}
+
+func (p *endedSpans) OnEnd(s sdktrace.ReadOnlySpan) {
+	p.count++
+}
+
+func TestDroppedSpans(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+
+	// The queue is full
+	next := &endedSpans{}
+	processor := NewSpanProcessor(tracetest.NewInMemoryExporter(), BatchConfig{MaxQueueSize: 2, MaxExportBatchSize: 2}).(*queueLimitProcessor)
+	require.NoError(t, processor.Shutdown(context.Background()))
+	processor.SpanProcessor = next
+	sampled := tracetest.SpanStub{SpanContext: trace.NewSpanContext(trace.SpanContextConfig{TraceFlags: trace.FlagsSampled})}.Snapshot()
+	for range 5 {
+		processor.OnEnd(sampled)
+	}
+	processor.OnEnd(tracetest.SpanStub{}.Snapshot())
+	assert.Equal(t, 2, next.count)
+
+	// The export fails
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(NewSpanProcessor(failingExporter{}, BatchConfig{Sync: true})))
+	_, span := tracerProvider.Tracer("test").Start(context.Background(), "test")
+	span.End()
+
+	data := metricdata.ResourceMetrics{}
+	require.NoError(t, reader.Collect(context.Background(), &data))
+	require.Len(t, data.ScopeMetrics, 1)
+	require.Len(t, data.ScopeMetrics[0].Metrics, 1)
+	assert.Equal(t, MetricDroppedSpans, data.ScopeMetrics[0].Metrics[0].Name)
+	dropped := map[string]int64{}
+	for _, point := range data.ScopeMetrics[0].Metrics[0].Data.(metricdata.Sum[int64]).DataPoints {
+		reason, _ := point.Attributes.Value(FieldDropReason)
+		dropped[reason.AsString()] = point.Value
+	}
+	assert.Equal(t, map[string]int64{DropReasonQueueFull: 3, DropReasonExportFailed: 1}, dropped)
+}
+
+type failingExporter struct{}
+
+func (failingExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	return errors.New("connection refused")
+}
+
+func (failingExporter) Shutdown(ctx context.Context) error {
+	return nil
+}
@@ -21,7 +21,7 @@ type OtelErrorHandler struct {
 }
 
 func (e *OtelErrorHandler) Handle(err error) {
-	e.log.Error("OTEL ERROR", logger.FieldError, err)
+	e.log.Error("OTEL ERROR", logger.ErrorAttr(err))
 }
 
 const (
@@ -44,14 +44,26 @@ var (
 
 // TODO:
 // initTracerProvider Initializes an OTLP exporter, and configures the corresponding tracer provider.
-func initTracerProvider(ctx context.Context, tracerExporter sdktrace.SpanExporter, res *resource.Resource) (*sdktrace.TracerProvider, error) {
-	// Set up a trace exporter
-	bsp := sdktrace.NewBatchSpanProcessor(tracerExporter)
+func initTracerProvider(ctx context.Context, tracerExporter sdktrace.SpanExporter, res *resource.Resource, config Config) (*sdktrace.TracerProvider, error) {
+	sampler, err := config.traceSampler()
+	if err != nil {
+		return nil, err
+	}
+	batch, err := config.batchConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	// The only span processor of the exporter, so the spans are exported once
+	processor := NewSpanProcessor(tracerExporter, batch)
+	if config.OtelTracesSamplerErrors {
+		processor = NewErrorSpanProcessor(processor)
+	}
 
 	tracerProvider := sdktrace.NewTracerProvider(
-		sdktrace.WithBatcher(tracerExporter),
 		sdktrace.WithResource(res),
-		sdktrace.WithSpanProcessor(bsp),
+		sdktrace.WithSpanProcessor(processor),
+		sdktrace.WithSampler(sampler),
 	)
 
 	if eh := otelErrorHandler.Load(); eh == nil {
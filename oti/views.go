@@ -0,0 +1,178 @@
+package oti
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+const (
+	// The options of the metric views, see ParseViews
+	ViewOptionName      = "name"
+	ViewOptionDrop      = "drop"
+	ViewOptionBuckets   = "buckets"
+	ViewOptionHistogram = "histogram"
+
+	// ViewHistogramExponential is the value of the histogram option of the base2 exponential bucket histograms
+	ViewHistogramExponential = "exponential"
+
+	// The parameters of the base2 exponential bucket histograms, the defaults of the OpenTelemetry specification
+	exponentialHistogramMaxSize  = 160
+	exponentialHistogramMaxScale = 20
+)
+
+// ViewConfig is the configuration of a metric view
+type ViewConfig struct {
+	// Pattern is the name of the instruments, `*` matches any characters, `?` matches one character, e.g. http.*
+	Pattern string
+	// Rename is the new name of the instrument, the pattern must not have wildcards if it is set
+	Rename string
+	// DropAttributes are the keys of the attributes dropped from the measurements
+	DropAttributes []string
+	// Buckets are the boundaries of the explicit bucket histograms
+	Buckets []float64
+	// Exponential makes the histograms base2 exponential bucket histograms
+	Exponential bool
+}
+
+// ParseViews parses the semicolon separated `pattern:option=value,...` views in the order of their precedence, where the options are
+// name=<new name>, drop=<key>|<key>..., buckets=<boundary>|<boundary>... or histogram=exponential,
+// e.g. `http.server.duration:buckets=0.01|0.1|1|10;rpc.*:histogram=exponential,drop=rpc.method`
+func ParseViews(s string) ([]ViewConfig, error) {
+	views := []ViewConfig{}
+	for _, spec := range strings.Split(s, ";") {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+		pattern, options, ok := strings.Cut(spec, ":")
+		view := ViewConfig{Pattern: strings.TrimSpace(pattern)}
+		if !ok || view.Pattern == "" {
+			return nil, fmt.Errorf("%w: wrong metric view: %s", ErrOtelConfig, spec)
+		}
+		for _, option := range strings.Split(options, ",") {
+			key, value, ok := strings.Cut(strings.TrimSpace(option), "=")
+			if !ok || value == "" {
+				return nil, fmt.Errorf("%w: wrong metric view option: %s", ErrOtelConfig, option)
+			}
+			switch key {
+			case ViewOptionName:
+				view.Rename = value
+			case ViewOptionDrop:
+				view.DropAttributes = strings.Split(value, "|")
+			case ViewOptionBuckets:
+				for _, boundary := range strings.Split(value, "|") {
+					b, err := strconv.ParseFloat(boundary, 64)
+					if err != nil {
+						return nil, fmt.Errorf("%w: wrong metric view bucket boundary: %s", ErrOtelConfig, boundary)
+					}
+					view.Buckets = append(view.Buckets, b)
+				}
+			case ViewOptionHistogram:
+				if value != ViewHistogramExponential {
+					return nil, fmt.Errorf("%w: wrong metric view histogram: %s", ErrOtelConfig, value)
+				}
+				view.Exponential = true
+			default:
+				return nil, fmt.Errorf("%w: wrong metric view option: %s", ErrOtelConfig, option)
+			}
+		}
+		if err := view.validate(); err != nil {
+			return nil, err
+		}
+		views = append(views, view)
+	}
+	return views, nil
+}
+
+func (v ViewConfig) validate() error {
+	if v.Rename != "" && strings.ContainsAny(v.Pattern, "*?") {
+		return fmt.Errorf("%w: the metric view of a pattern with wildcards can not rename: %s", ErrOtelConfig, v.Pattern)
+	}
+	if len(v.Buckets) > 0 && v.Exponential {
+		return fmt.Errorf("%w: the metric view has both buckets and exponential histogram: %s", ErrOtelConfig, v.Pattern)
+	}
+	if !slices.IsSorted(v.Buckets) || len(slices.Compact(slices.Clone(v.Buckets))) != len(v.Buckets) {
+		return fmt.Errorf("%w: the bucket boundaries of the metric view are not increasing: %s", ErrOtelConfig, v.Pattern)
+	}
+	return nil
+}
+
+// View creates the metric view. The rename and the dropped attributes are applied to every kind of the matching instruments,
+// the histogram aggregations only to the histograms.
+func (v ViewConfig) View() sdkmetric.View {
+	mask := sdkmetric.Stream{Name: v.Rename}
+	if len(v.DropAttributes) > 0 {
+		keys := make([]attribute.Key, len(v.DropAttributes))
+		for i, key := range v.DropAttributes {
+			keys[i] = attribute.Key(key)
+		}
+		mask.AttributeFilter = attribute.NewDenyKeysFilter(keys...)
+	}
+	view := sdkmetric.NewView(sdkmetric.Instrument{Name: v.Pattern}, mask)
+
+	switch {
+	case len(v.Buckets) > 0:
+		mask.Aggregation = sdkmetric.AggregationExplicitBucketHistogram{Boundaries: v.Buckets}
+	case v.Exponential:
+		mask.Aggregation = sdkmetric.AggregationBase2ExponentialHistogram{
+			MaxSize:  exponentialHistogramMaxSize,
+			MaxScale: exponentialHistogramMaxScale,
+		}
+	default:
+		return view
+	}
+	histogramView := sdkmetric.NewView(sdkmetric.Instrument{Name: v.Pattern, Kind: sdkmetric.InstrumentKindHistogram}, mask)
+	return func(i sdkmetric.Instrument) (sdkmetric.Stream, bool) {
+		if stream, ok := histogramView(i); ok {
+			return stream, true
+		}
+		return view(i)
+	}
+}
+
+// firstMatchView applies the first of the views matching the instrument, so an instrument is exported once,
+// even if the patterns of several views match it
+func firstMatchView(views []sdkmetric.View) sdkmetric.View {
+	return func(i sdkmetric.Instrument) (sdkmetric.Stream, bool) {
+		for _, view := range views {
+			if stream, ok := view(i); ok {
+				return stream, true
+			}
+		}
+		return sdkmetric.Stream{}, false
+	}
+}
+
+// meterProviderOptions returns with the views of the meter providers configured by OtelMetricViews.
+// Only the first matching view is applied to an instrument.
+func (cfg *Config) meterProviderOptions() ([]sdkmetric.Option, error) {
+	configs, err := ParseViews(cfg.OtelMetricViews)
+	if err != nil {
+		return nil, err
+	}
+	if len(configs) == 0 {
+		return []sdkmetric.Option{}, nil
+	}
+	views := make([]sdkmetric.View, len(configs))
+	for i, config := range configs {
+		views[i] = config.View()
+	}
+	return []sdkmetric.Option{sdkmetric.WithView(firstMatchView(views))}, nil
+}
+
+// periodicReaderOptions returns with the export interval and timeout of the periodic readers, the defaults are used if they are 0
+func (cfg *Config) periodicReaderOptions() ([]sdkmetric.PeriodicReaderOption, error) {
+	if cfg.OtelMetricExportInterval < 0 || cfg.OtelMetricExportTimeout < 0 {
+		return nil, fmt.Errorf("%w: the metric export interval and timeout must not be negative", ErrOtelConfig)
+	}
+	return []sdkmetric.PeriodicReaderOption{
+		sdkmetric.WithInterval(time.Duration(cfg.OtelMetricExportInterval) * time.Millisecond),
+		sdkmetric.WithTimeout(time.Duration(cfg.OtelMetricExportTimeout) * time.Millisecond),
+	}, nil
+}
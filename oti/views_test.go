@@ -0,0 +1,109 @@
+package oti
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	metric_api "go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestParseViews(t *testing.T) {
+	views, err := ParseViews(" http.server.duration:name=http.duration,buckets=0.1|1|10; rpc.*:histogram=exponential,drop=rpc.method|rpc.service;")
+	require.NoError(t, err)
+	assert.Equal(t, []ViewConfig{
+		{Pattern: "http.server.duration", Rename: "http.duration", Buckets: []float64{0.1, 1, 10}},
+		{Pattern: "rpc.*", DropAttributes: []string{"rpc.method", "rpc.service"}, Exponential: true},
+	}, views)
+
+	for _, spec := range []string{
+		"http.server.duration",
+		":name=duration",
+		"http.*:name=duration",
+		"http.server.duration:buckets=1|one",
+		"http.server.duration:buckets=10|1",
+		"http.server.duration:buckets=1|1",
+		"http.server.duration:buckets=1,histogram=exponential",
+		"http.server.duration:histogram=linear",
+		"http.server.duration:unit=ms",
+		"http.server.duration:drop=",
+	} {
+		_, err := ParseViews(spec)
+		assert.ErrorIs(t, err, ErrOtelConfig, spec)
+	}
+}
+
+// collectMetrics records a histogram and a counter with the user and path attributes by the meter provider of the views
+func collectMetrics(t *testing.T, views string) map[string]metricdata.Metrics {
+	options, err := (&Config{OtelMetricViews: views}).meterProviderOptions()
+	require.NoError(t, err)
+	reader := sdkmetric.NewManualReader()
+	meter := sdkmetric.NewMeterProvider(append(options, sdkmetric.WithReader(reader))...).Meter("test")
+	attrs := metric_api.WithAttributes(attribute.String("user", "joe"), attribute.String("path", "/"))
+
+	histogram, err := meter.Float64Histogram("request.duration")
+	require.NoError(t, err)
+	histogram.Record(context.Background(), 5, attrs)
+	counter, err := meter.Int64Counter("request.count")
+	require.NoError(t, err)
+	counter.Add(context.Background(), 1, attrs)
+
+	data := metricdata.ResourceMetrics{}
+	require.NoError(t, reader.Collect(context.Background(), &data))
+	require.Len(t, data.ScopeMetrics, 1)
+	metrics := map[string]metricdata.Metrics{}
+	for _, m := range data.ScopeMetrics[0].Metrics {
+		metrics[m.Name] = m
+	}
+	return metrics
+}
+
+func TestMeterProviderViews(t *testing.T) {
+	metrics := collectMetrics(t, "request.duration:name=duration,buckets=1|10,drop=user;request.*:drop=path")
+
+	// Only the first matching view is applied to the histogram
+	require.Len(t, metrics, 2)
+	require.Contains(t, metrics, "duration")
+	points := metrics["duration"].Data.(metricdata.Histogram[float64]).DataPoints
+	require.Len(t, points, 1)
+	assert.Equal(t, []float64{1, 10}, points[0].Bounds)
+	assert.Equal(t, []uint64{0, 1, 0}, points[0].BucketCounts)
+	assert.False(t, points[0].Attributes.HasValue("user"))
+	assert.True(t, points[0].Attributes.HasValue("path"))
+
+	require.Contains(t, metrics, "request.count")
+	sum := metrics["request.count"].Data.(metricdata.Sum[int64]).DataPoints
+	require.Len(t, sum, 1)
+	assert.False(t, sum[0].Attributes.HasValue("path"))
+	assert.True(t, sum[0].Attributes.HasValue("user"))
+}
+
+func TestMeterProviderHistogramViewDropsAttributesOfAllKinds(t *testing.T) {
+	metrics := collectMetrics(t, "request.*:histogram=exponential,drop=user")
+
+	require.Contains(t, metrics, "request.duration")
+	points := metrics["request.duration"].Data.(metricdata.ExponentialHistogram[float64]).DataPoints
+	require.Len(t, points, 1)
+	assert.False(t, points[0].Attributes.HasValue("user"))
+
+	require.Contains(t, metrics, "request.count")
+	sum := metrics["request.count"].Data.(metricdata.Sum[int64]).DataPoints
+	require.Len(t, sum, 1)
+	assert.False(t, sum[0].Attributes.HasValue("user"))
+	assert.True(t, sum[0].Attributes.HasValue("path"))
+}
+
+func TestPeriodicReaderOptions(t *testing.T) {
+	options, err := (&Config{OtelMetricExportInterval: 1000, OtelMetricExportTimeout: 500}).periodicReaderOptions()
+	require.NoError(t, err)
+	assert.Len(t, options, 2)
+
+	_, err = (&Config{OtelMetricExportInterval: -1}).periodicReaderOptions()
+	assert.ErrorIs(t, err, ErrOtelConfig)
+	_, err = (&Config{OtelMetricExportTimeout: -1}).periodicReaderOptions()
+	assert.ErrorIs(t, err, ErrOtelConfig)
+}